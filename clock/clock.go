@@ -0,0 +1,287 @@
+package clock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// System selects how players get time for their moves.
+type System int
+
+const (
+	// NoTime games are not timed.
+	NoTime System = iota
+	// Absolute gives players main time for the whole game.
+	Absolute
+	// Fischer adds increment to the main time after every move.
+	Fischer
+	// ByoYomi gives players periods after the main time, a period is only used up when the move takes longer.
+	ByoYomi
+	// Canadian gives players blocks of time in which a number of moves has to be made after the main time.
+	Canadian
+)
+
+var systems = map[string]System{
+	"NONE":     NoTime,
+	"ABSOLUTE": Absolute,
+	"FISCHER":  Fischer,
+	"BYOYOMI":  ByoYomi,
+	"CANADIAN": Canadian,
+}
+
+// SystemByName returns time control system by name. Unknown names default to untimed games.
+func SystemByName(name string) System {
+	name = strings.ReplaceAll(strings.ToUpper(name), "-", "")
+	if s, ok := systems[name]; ok {
+		return s
+	}
+	return NoTime
+}
+
+// Settings configure time control of both players.
+type Settings struct {
+	System   System
+	MainTime time.Duration
+	// Increment is added after every move with fischer timing.
+	Increment time.Duration
+	// Period is length of byo-yomi periods and canadian overtime blocks.
+	Period time.Duration
+	// Periods is number of byo-yomi periods.
+	Periods int
+	// Stones is number of moves that have to be made in a canadian overtime block.
+	Stones int
+}
+
+// Overtime describes overtime settings in the format used by SGF OT property (empty without overtime).
+func (s Settings) Overtime() string {
+	switch s.System {
+	case Fischer:
+		return fmt.Sprintf("%g fischer", s.Increment.Seconds())
+	case ByoYomi:
+		return fmt.Sprintf("%dx%g byo-yomi", s.Periods, s.Period.Seconds())
+	case Canadian:
+		return fmt.Sprintf("%d/%g canadian", s.Stones, s.Period.Seconds())
+	}
+	return ""
+}
+
+// ParseSettings reads time control from main time in seconds and overtime description written by Overtime.
+// Unknown overtime descriptions are read as absolute time.
+func ParseSettings(mainTime, overtime string) Settings {
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(mainTime), 64)
+	if err != nil {
+		return Settings{}
+	}
+	s := Settings{
+		System:   Absolute,
+		MainTime: duration(seconds),
+	}
+
+	fields := strings.Fields(strings.ToLower(overtime))
+	if len(fields) != 2 {
+		return s
+	}
+	switch fields[1] {
+	case "fischer":
+		if increment, err := strconv.ParseFloat(fields[0], 64); err == nil {
+			s.System = Fischer
+			s.Increment = duration(increment)
+		}
+	case "byo-yomi":
+		periods, period, ok := strings.Cut(fields[0], "x")
+		n, errN := strconv.Atoi(periods)
+		length, errL := strconv.ParseFloat(period, 64)
+		if ok && errN == nil && errL == nil {
+			s.System = ByoYomi
+			s.Periods = n
+			s.Period = duration(length)
+		}
+	case "canadian":
+		stones, block, ok := strings.Cut(fields[0], "/")
+		n, errN := strconv.Atoi(stones)
+		length, errL := strconv.ParseFloat(block, 64)
+		if ok && errN == nil && errL == nil {
+			s.System = Canadian
+			s.Stones = n
+			s.Period = duration(length)
+		}
+	}
+	return s
+}
+
+func duration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// Clock tracks time left to a single player. Time is passed in explicitly so clocks can be driven by any
+// time source.
+type Clock struct {
+	Settings Settings
+	// Main is main time left.
+	Main time.Duration
+	// Period is time left in the current byo-yomi period or canadian block.
+	Period      time.Duration
+	PeriodsLeft int
+	StonesLeft  int
+	Flagged     bool
+
+	started time.Time
+	running bool
+}
+
+func NewClock(settings Settings) *Clock {
+	return &Clock{
+		Settings:    settings,
+		Main:        settings.MainTime,
+		Period:      settings.Period,
+		PeriodsLeft: settings.Periods,
+		StonesLeft:  settings.Stones,
+	}
+}
+
+// Running returns wether the clock is counting down.
+func (c *Clock) Running() bool {
+	return c.running
+}
+
+// Start starts counting down the time of the player to move.
+func (c *Clock) Start(now time.Time) {
+	if c.running || c.Settings.System == NoTime {
+		return
+	}
+	c.started = now
+	c.running = true
+}
+
+// Stop stops the clock after the player made their move and adds overtime for the next move. Returns false
+// when the player ran out of time.
+func (c *Clock) Stop(now time.Time) bool {
+	if !c.running {
+		return !c.Flagged
+	}
+	c.running = false
+	if !c.spend(now.Sub(c.started)) {
+		return false
+	}
+
+	overtime := c.Main == 0
+	switch c.Settings.System {
+	case Fischer:
+		c.Main += c.Settings.Increment
+	case ByoYomi:
+		if overtime {
+			c.Period = c.Settings.Period
+		}
+	case Canadian:
+		if overtime {
+			c.StonesLeft--
+			if c.StonesLeft <= 0 {
+				c.StonesLeft = c.Settings.Stones
+				c.Period = c.Settings.Period
+			}
+		}
+	}
+	return true
+}
+
+// Pause stops the clock without finishing the move, time used so far is taken from the clock. Returns false
+// when the player ran out of time.
+func (c *Clock) Pause(now time.Time) bool {
+	if !c.running {
+		return !c.Flagged
+	}
+	c.running = false
+	return c.spend(now.Sub(c.started))
+}
+
+// spend takes the time used by the move from the clock. Returns false when the player ran out of time.
+func (c *Clock) spend(used time.Duration) bool {
+	if c.Main > used {
+		c.Main -= used
+		return true
+	}
+	used -= c.Main
+	c.Main = 0
+
+	switch c.Settings.System {
+	case ByoYomi:
+		for c.PeriodsLeft > 0 && used >= c.Period {
+			used -= c.Period
+			c.PeriodsLeft--
+			c.Period = c.Settings.Period
+		}
+		if c.PeriodsLeft > 0 {
+			c.Period -= used
+			return true
+		}
+	case Canadian:
+		if used < c.Period {
+			c.Period -= used
+			return true
+		}
+	}
+	c.Flagged = true
+	return false
+}
+
+// total returns time left for the current move when it was started.
+func (c *Clock) total() time.Duration {
+	switch c.Settings.System {
+	case ByoYomi:
+		if c.PeriodsLeft == 0 {
+			return c.Main
+		}
+		return c.Main + c.Period + time.Duration(c.PeriodsLeft-1)*c.Settings.Period
+	case Canadian:
+		return c.Main + c.Period
+	}
+	return c.Main
+}
+
+// Remaining returns time the player can still think about the current move.
+func (c *Clock) Remaining(now time.Time) time.Duration {
+	if c.Flagged {
+		return 0
+	}
+	left := c.total()
+	if c.running {
+		left -= now.Sub(c.started)
+	}
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// Expired returns wether the player ran out of time. Untimed clocks never expire.
+func (c *Clock) Expired(now time.Time) bool {
+	return c.Settings.System != NoTime && (c.Flagged || c.Remaining(now) <= 0)
+}
+
+// String shows time left on the clock (main time, byo-yomi periods or canadian stones left in the block).
+func (c *Clock) String(now time.Time) string {
+	if c.Settings.System == NoTime {
+		return "-"
+	}
+	if c.Flagged {
+		return "0:00"
+	}
+	view := *c
+	if view.running && !view.spend(now.Sub(view.started)) {
+		return "0:00"
+	}
+	if view.Main > 0 || c.Settings.System == Absolute || c.Settings.System == Fischer {
+		return format(view.Main)
+	}
+	if c.Settings.System == ByoYomi {
+		return fmt.Sprintf("%s (%d)", format(view.Period), view.PeriodsLeft)
+	}
+	return fmt.Sprintf("%s/%d", format(view.Period), view.StonesLeft)
+}
+
+func format(d time.Duration) string {
+	seconds := int(d.Seconds())
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,186 @@
+package clock
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	type step struct {
+		// used is time spent on the move, paused moves continue in the next step
+		used  time.Duration
+		pause bool
+		ok    bool
+	}
+	s := time.Second
+
+	tests := []struct {
+		name     string
+		settings Settings
+		steps    []step
+		// clock state after the last step
+		main        time.Duration
+		period      time.Duration
+		periodsLeft int
+		stonesLeft  int
+		flagged     bool
+	}{
+		{
+			name:     "untimed",
+			settings: Settings{},
+			steps:    []step{{used: time.Hour, ok: true}},
+		},
+		{
+			name:     "absolute",
+			settings: Settings{System: Absolute, MainTime: 10 * s},
+			steps:    []step{{used: 4 * s, ok: true}},
+			main:     6 * s,
+		},
+		{
+			name:     "absolute using all time",
+			settings: Settings{System: Absolute, MainTime: 10 * s},
+			steps:    []step{{used: 4 * s, ok: true}, {used: 6 * s, ok: false}},
+			flagged:  true,
+		},
+		{
+			name:     "fischer increment",
+			settings: Settings{System: Fischer, MainTime: 10 * s, Increment: 5 * s},
+			steps:    []step{{used: 3 * s, ok: true}, {used: 4 * s, ok: true}},
+			main:     13 * s,
+		},
+		{
+			name:     "fischer increment after pause",
+			settings: Settings{System: Fischer, MainTime: 10 * s, Increment: 5 * s},
+			steps:    []step{{used: 3 * s, pause: true, ok: true}, {used: 4 * s, ok: true}},
+			main:     8 * s,
+		},
+		{
+			name:     "fischer out of time",
+			settings: Settings{System: Fischer, MainTime: 10 * s, Increment: 5 * s},
+			steps:    []step{{used: 11 * s, ok: false}},
+			flagged:  true,
+		},
+		{
+			name:        "byo-yomi period is reset",
+			settings:    Settings{System: ByoYomi, MainTime: 10 * s, Period: 30 * s, Periods: 3},
+			steps:       []step{{used: 15 * s, ok: true}},
+			period:      30 * s,
+			periodsLeft: 3,
+		},
+		{
+			name:        "byo-yomi periods used up",
+			settings:    Settings{System: ByoYomi, MainTime: 10 * s, Period: 30 * s, Periods: 3},
+			steps:       []step{{used: 75 * s, ok: true}},
+			period:      30 * s,
+			periodsLeft: 1,
+		},
+		{
+			name:        "byo-yomi period continues after pause",
+			settings:    Settings{System: ByoYomi, Period: 30 * s, Periods: 3},
+			steps:       []step{{used: 10 * s, pause: true, ok: true}, {used: 10 * s, pause: true, ok: true}},
+			period:      10 * s,
+			periodsLeft: 3,
+		},
+		{
+			name:     "byo-yomi out of time",
+			settings: Settings{System: ByoYomi, MainTime: 10 * s, Period: 30 * s, Periods: 2},
+			steps:    []step{{used: 70 * s, ok: false}},
+			period:   30 * s,
+			flagged:  true,
+		},
+		{
+			name:     "canadian block",
+			settings: Settings{System: Canadian, MainTime: 10 * s, Period: 60 * s, Stones: 2},
+			steps: []step{
+				{used: 10 * s, ok: true},
+				{used: 20 * s, ok: true},
+				{used: 50 * s, ok: true},
+			},
+			period:     10 * s,
+			stonesLeft: 1,
+		},
+		{
+			name:     "canadian out of time",
+			settings: Settings{System: Canadian, MainTime: 10 * s, Period: 60 * s, Stones: 2},
+			steps: []step{
+				{used: 10 * s, ok: true},
+				{used: 20 * s, ok: true},
+				{used: 50 * s, ok: true},
+				{used: 15 * s, ok: false},
+			},
+			period:     10 * s,
+			stonesLeft: 1,
+			flagged:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewClock(test.settings)
+			now := time.Unix(0, 0)
+			for i, st := range test.steps {
+				c.Start(now)
+				now = now.Add(st.used)
+				var ok bool
+				if st.pause {
+					ok = c.Pause(now)
+				} else {
+					ok = c.Stop(now)
+				}
+				if ok != st.ok {
+					t.Fatalf("step %d returned %t, expected %t", i, ok, st.ok)
+				}
+			}
+
+			if c.Main != test.main || c.Period != test.period || c.PeriodsLeft != test.periodsLeft || c.StonesLeft != test.stonesLeft || c.Flagged != test.flagged {
+				t.Fatalf("clock main %s, period %s, periods %d, stones %d, flagged %t, expected %s, %s, %d, %d, %t",
+					c.Main, c.Period, c.PeriodsLeft, c.StonesLeft, c.Flagged,
+					test.main, test.period, test.periodsLeft, test.stonesLeft, test.flagged)
+			}
+			if expired := c.Expired(now); expired != test.flagged {
+				t.Fatalf("clock expired %t, expected %t", expired, test.flagged)
+			}
+		})
+	}
+}
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+	}{
+		{
+			name:     "absolute",
+			settings: Settings{System: Absolute, MainTime: 10 * time.Minute},
+		},
+		{
+			name:     "fischer",
+			settings: Settings{System: Fischer, MainTime: 5 * time.Minute, Increment: 2500 * time.Millisecond},
+		},
+		{
+			name:     "byo-yomi",
+			settings: Settings{System: ByoYomi, MainTime: time.Hour, Period: 30 * time.Second, Periods: 5},
+		},
+		{
+			name:     "canadian",
+			settings: Settings{System: Canadian, Period: 5 * time.Minute, Stones: 25},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mainTime := strconv.FormatFloat(test.settings.MainTime.Seconds(), 'g', -1, 64)
+			if s := ParseSettings(mainTime, test.settings.Overtime()); s != test.settings {
+				t.Fatalf("parsed settings %+v, expected %+v (overtime %q)", s, test.settings, test.settings.Overtime())
+			}
+		})
+	}
+
+	if s := ParseSettings("60", "3 hourglass"); s != (Settings{System: Absolute, MainTime: time.Minute}) {
+		t.Fatalf("unknown overtime parsed as %+v, expected absolute time", s)
+	}
+	if s := ParseSettings("one minute", ""); s != (Settings{}) {
+		t.Fatalf("invalid main time parsed as %+v, expected untimed settings", s)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"math/rand"
+	"os"
+
+	"github.com/al-pi314/gogo"
+	"github.com/al-pi314/gogo/gtp"
+	"github.com/al-pi314/gogo/population"
+	"github.com/al-pi314/gogo/rules"
+	"github.com/spf13/viper"
+)
+
+func loadConfig() *gogo.Config {
+	viper.SetEnvPrefix("X")
+	viper.SetConfigFile(".env")
+	viper.ReadInConfig()
+
+	config := gogo.Config{}
+	viper.Unmarshal(&config)
+
+	rand.Seed(config.RandomSeed)
+	return &config
+}
+
+func main() {
+	config := loadConfig()
+
+	populationFile := flag.String("population", "", "population file to load the agent from")
+	agentIdx := flag.Int("agent", 0, "index of the agent inside of the population")
+	fake := flag.Bool("fake", false, "play the first empty position instead of an agent move (no population required)")
+	flag.Parse()
+
+	// standard output is reserved for gtp responses
+	out := os.Stdout
+	os.Stdout = os.Stderr
+
+	// handicap pieces are placed by the controller
+	engine := gtp.Engine{
+		Name:    "gogo",
+		Version: "1.0",
+		Settings: rules.Settings{
+			Superko: rules.SuperkoByName(config.Superko),
+			Ruleset: rules.RulesetByName(config.Ruleset),
+			Komi:    config.Komi,
+		},
+		Dymension: config.Dymension,
+	}
+
+	if *fake {
+		engine.Player = &gtp.FakePlayer{}
+	} else {
+		p := population.Population{GameRules: engine.Settings}
+		if !p.LoadFromFile(populationFile) {
+			log.Fatal("population file is required, use -population flag")
+		}
+		agent := p.FirstNthAgent(*agentIdx)
+		if agent == nil {
+			log.Fatalf("population has no agent %d", *agentIdx)
+		}
+
+		engine.Player = agent
+		engine.Dymension = p.GameDymension
+		// agent network input size depends on the board size
+		engine.FixedDymension = true
+	}
+
+	if err := gtp.NewEngine(engine).Run(os.Stdin, out); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -5,12 +5,17 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"strings"
+	"time"
 
 	"github.com/al-pi314/gogo"
+	"github.com/al-pi314/gogo/clock"
 	"github.com/al-pi314/gogo/game"
 	"github.com/al-pi314/gogo/player"
 	"github.com/al-pi314/gogo/population"
+	"github.com/al-pi314/gogo/rules"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
 
@@ -26,18 +31,39 @@ func loadConfig() *gogo.Config {
 	return &config
 }
 
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
 func isArgSet(arg *string) bool {
 	return arg != nil && *arg != ""
 }
 
+func startEngine(command string) *player.GTP {
+	p, err := player.NewGTP(player.GTP{
+		Command: strings.Fields(command),
+	})
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "failed to start gtp player, use -engine flag"))
+	}
+	return p
+}
+
 func main() {
 	config := loadConfig()
 
-	white := flag.String("white", "human", "set to 'human' or to 'agent'")
-	black := flag.String("black", "human", "set to 'human' or to 'agent'")
+	white := flag.String("white", "human", "set to 'human', 'agent', 'gtp', 'mcts', 'alphabeta' or baseline player ('random', 'eyes', 'capture', 'atari')")
+	black := flag.String("black", "human", "set to 'human', 'agent', 'gtp', 'mcts', 'alphabeta' or baseline player ('random', 'eyes', 'capture', 'atari')")
+	engine := flag.String("engine", "", "command starting GTP engine used by 'gtp' players")
 	populationFile := flag.String("population", "", "population file to use for agent players")
 	moveDelay := flag.Int("delay", 0, "miliseconds to wait after each move not made by human")
 	replay := flag.String("replay", "", "game save file to replay")
+	save := flag.String("save", "", "file to save the game to when it ends (.sgf or .json)")
+	heatmap := flag.Bool("heatmap", false, "show agent policy heatmap, can be toggled with H")
+	playouts := flag.Int("playouts", 1000, "number of playouts per move of 'mcts' players")
+	budget := flag.Int("budget", 0, "miliseconds 'mcts' and 'alphabeta' players can think about a move (0 for no limit)")
+	depth := flag.Int("depth", 0, "search depth of 'alphabeta' players (0 for default or no limit when budget is set)")
+	evaluator := flag.String("evaluator", "influence", "position evaluation of 'alphabeta' players ('influence', 'territory' or 'network')")
 	flag.Parse()
 
 	var whitePlayer player.Player
@@ -45,10 +71,12 @@ func main() {
 	whitePlayer = player.NewHuman(player.Human{
 		XSnap: config.SquareSize + config.BorderSize,
 		YSnap: config.SquareSize + config.BorderSize,
+		Input: game.Input{},
 	})
 	blackPlayer = player.NewHuman(player.Human{
 		XSnap: config.SquareSize + config.BorderSize,
 		YSnap: config.SquareSize + config.BorderSize,
+		Input: game.Input{},
 	})
 
 	population := population.NewPopulation(config)
@@ -64,20 +92,89 @@ func main() {
 		}
 	}
 
+	if isArgSet(white) && *white == "gtp" {
+		whitePlayer = startEngine(*engine)
+	}
+	if isArgSet(black) && *black == "gtp" {
+		blackPlayer = startEngine(*engine)
+	}
+	// search players use agents from the population as move prior
+	mcts := func(agent int, seed int64) player.Player {
+		p := player.MCTS{
+			Playouts: *playouts,
+			Budget:   time.Duration(*budget) * time.Millisecond,
+			Seed:     seed,
+		}
+		if isArgSet(populationFile) {
+			p.Network = population.FirstNthAgent(agent).Logic
+		}
+		return player.NewMCTS(p)
+	}
+	if *white == "mcts" {
+		whitePlayer = mcts(0, config.RandomSeed)
+	}
+	if *black == "mcts" {
+		blackPlayer = mcts(1, config.RandomSeed+1)
+	}
+	alphaBeta := func(agent int) player.Player {
+		p := player.AlphaBeta{
+			Depth:  *depth,
+			Budget: time.Duration(*budget) * time.Millisecond,
+		}
+		switch *evaluator {
+		case "territory":
+			p.Evaluator = player.TerritoryEvaluator{}
+		case "network":
+			if !isArgSet(populationFile) {
+				log.Fatal("network evaluator requires a population, use -population flag")
+			}
+			p.Evaluator = player.NetworkEvaluator{Network: population.FirstNthAgent(agent).Logic}
+		}
+		return player.NewAlphaBeta(p)
+	}
+	if *white == "alphabeta" {
+		whitePlayer = alphaBeta(0)
+	}
+	if *black == "alphabeta" {
+		blackPlayer = alphaBeta(1)
+	}
+	if p, err := player.NewBaseline(*white, config.RandomSeed); err == nil {
+		whitePlayer = p
+	}
+	if p, err := player.NewBaseline(*black, config.RandomSeed+1); err == nil {
+		blackPlayer = p
+	}
+
 	game := game.NewGame(game.Game{
-		Dymension:   config.Dymension,
-		SquareSize:  config.SquareSize,
-		BorderSize:  config.BorderSize,
-		WhitePlayer: whitePlayer,
-		BlackPlayer: blackPlayer,
-		MoveDelay:   moveDelay,
+		SaveFileName: *save,
+		WhiteName:    *white,
+		BlackName:    *black,
+		Dymension:    config.Dymension,
+		SquareSize:   config.SquareSize,
+		BorderSize:   config.BorderSize,
+		WhitePlayer:  whitePlayer,
+		BlackPlayer:  blackPlayer,
+		MoveDelay:    moveDelay,
+		Superko:      rules.SuperkoByName(config.Superko),
+		Ruleset:      rules.RulesetByName(config.Ruleset),
+		Komi:         config.Komi,
+		Handicap:     config.Handicap,
+		FreeHandicap: config.FreeHandicap,
+		MoveLimit:    config.MoveLimit,
+		Heatmap:      *heatmap,
+		TimeControl: clock.Settings{
+			System:    clock.SystemByName(config.TimeControl),
+			MainTime:  seconds(config.MainTime),
+			Increment: seconds(config.TimeIncrement),
+			Period:    seconds(config.OvertimePeriod),
+			Periods:   config.OvertimePeriods,
+			Stones:    config.OvertimeStones,
+		},
 	})
 
 	if isArgSet(replay) {
 		game.ReplayFromFile(*replay)
-		if moveDelay == nil {
-			fmt.Println("WARRNING: move delay not set, use -delay flag to set replay pace")
-		}
+		fmt.Println("...replay controls: space play/pause, arrows step and change speed, home/end, type move number and enter to jump")
 	}
 
 	ebiten.SetTPS(ebiten.SyncWithFPS)
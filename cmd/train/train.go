@@ -12,8 +12,8 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/al-pi314/gogo"
-	"github.com/al-pi314/gogo/game"
 	"github.com/al-pi314/gogo/population"
+	"github.com/al-pi314/gogo/record"
 	"github.com/spf13/viper"
 )
 
@@ -61,8 +61,14 @@ func main() {
 
 	populationFile := flag.String("population", "", "path to population.json file containing a population")
 	outputDirectory := flag.String("output", "", "path to output directory for training")
+	leaderboard := flag.Bool("leaderboard", false, "print ratings of the population agents and exit")
 	flag.Parse()
 
+	fitness, err := population.FitnessByName(config.Fitness)
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "invalid FITNESS setting"))
+	}
+
 	// create population
 	currPopulation := population.NewPopulation(config)
 	fmt.Println("...population created")
@@ -70,6 +76,10 @@ func main() {
 		currPopulation.LoadFromFile(populationFile)
 		fmt.Println("...population overwritten from file")
 	}
+	if *leaderboard {
+		currPopulation.PrintLeaderboard(0)
+		return
+	}
 
 	// select output directory
 	if isArgSet(outputDirectory) {
@@ -86,9 +96,9 @@ func main() {
 
 	// test save population
 	currPopulation.Save()
-	game.NewGame(game.Game{
-		SaveFileName: fmt.Sprintf("%s/games/dummy_game.json", config.OutputDirectory),
-	}).Save()
+	record.Save(fmt.Sprintf("%s/games/dummy_game.sgf", config.OutputDirectory), record.Record{
+		Dymension: config.Dymension,
+	})
 	fmt.Println("...test save completed (check output directory!)")
 
 	// train population
@@ -100,6 +110,18 @@ func main() {
 
 		SaveInterval:     config.SaveInterval,
 		SaveGameInterval: config.SaveGameInterval,
+
+		Workers:            config.Workers,
+		Seed:               config.RandomSeed,
+		Leaderboard:        config.Leaderboard,
+		Fitness:            fitness,
+		HallOfFameInterval: config.HallOfFameInterval,
+		HallOfFameSize:     config.HallOfFameSize,
+		Benchmark: population.BenchmarkSettings{
+			Agents:     config.BenchmarkAgents,
+			HallOfFame: config.BenchmarkHallOfFame,
+			Games:      config.BenchmarkGames,
+		},
 	})
 
 	fmt.Println("...training completed")
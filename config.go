@@ -6,6 +6,22 @@ type Config struct {
 	SquareSize int `mapstructure:"square_size"`
 	BorderSize int `mapstructure:"border_size"`
 
+	// RULES
+	Superko      string  `mapstructure:"superko"`
+	Ruleset      string  `mapstructure:"ruleset"`
+	Komi         float64 `mapstructure:"komi"`
+	Handicap     int     `mapstructure:"handicap"`
+	FreeHandicap bool    `mapstructure:"free_handicap"`
+	MoveLimit    int     `mapstructure:"move_limit"`
+
+	// TIME (durations in seconds)
+	TimeControl     string  `mapstructure:"time_control"`
+	MainTime        float64 `mapstructure:"main_time"`
+	TimeIncrement   float64 `mapstructure:"time_increment"`
+	OvertimePeriod  float64 `mapstructure:"overtime_period"`
+	OvertimePeriods int     `mapstructure:"overtime_periods"`
+	OvertimeStones  int     `mapstructure:"overtime_stones"`
+
 	// CORE
 	RandomSeed int64 `mapstructure:"random_seed"`
 
@@ -24,4 +40,17 @@ type Config struct {
 	SaveInterval      int     `mapstructure:"save_interval"`
 	SaveGameInterval  int     `mapstructure:"save_game_interval"`
 	OutputDirectory   string  `mapstructure:"output_directory"`
+	ResignMargin      float64 `mapstructure:"resign_margin"`
+	Workers           int     `mapstructure:"workers"`
+	Leaderboard       int     `mapstructure:"leaderboard"`
+	Fitness           string  `mapstructure:"fitness"`
+
+	// HALL OF FAME
+	HallOfFameInterval int `mapstructure:"hall_of_fame_interval"`
+	HallOfFameSize     int `mapstructure:"hall_of_fame_size"`
+
+	// BENCHMARK
+	BenchmarkAgents     int `mapstructure:"benchmark_agents"`
+	BenchmarkHallOfFame int `mapstructure:"benchmark_hall_of_fame"`
+	BenchmarkGames      int `mapstructure:"benchmark_games"`
 }
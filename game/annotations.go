@@ -0,0 +1,112 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+
+	"github.com/al-pi314/gogo/gtp"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font/basicfont"
+)
+
+const (
+	labelMargin = 20
+	// statusLineHeight is height of a single status bar line
+	statusLineHeight = 20
+)
+
+// cellCenter returns position of the board cell center on the board image.
+func (g *Game) cellCenter(x, y int) (float64, float64) {
+	return float64((x+1)*(g.SquareSize+g.BorderSize) - g.SquareSize/2), float64((y+1)*(g.SquareSize+g.BorderSize) - g.SquareSize/2)
+}
+
+// drawLabels draws column letters and row numbers around the board.
+func (g *Game) drawLabels(screen *ebiten.Image) {
+	face := basicfont.Face7x13
+	far := labelMargin + g.boardSide()
+	for i := 0; i < g.Dymension; i++ {
+		cx, cy := g.cellCenter(i, i)
+		column := gtp.Column(i)
+		row := strconv.Itoa(g.Dymension - i)
+
+		x := labelMargin + int(cx) - face.Width*len(column)/2
+		text.Draw(screen, column, face, x, labelMargin/2+face.Ascent/2, color.Black)
+		text.Draw(screen, column, face, x, far+labelMargin/2+face.Ascent/2, color.Black)
+
+		y := labelMargin + int(cy) + face.Ascent/2
+		text.Draw(screen, row, face, labelMargin/2-face.Width*len(row)/2, y, color.Black)
+		text.Draw(screen, row, face, far+labelMargin/2-face.Width*len(row)/2, y, color.Black)
+	}
+}
+
+// drawLastMove marks the last placed piece.
+func (g *Game) drawLastMove(board *ebiten.Image) {
+	state := g.State()
+	if len(state.Moves) == 0 {
+		return
+	}
+	last := state.Moves[len(state.Moves)-1]
+	if last[0] == nil || last[1] == nil {
+		return
+	}
+	x, y := g.cellCenter(*last[0], *last[1])
+	ebitenutil.DrawCircle(board, x, y, float64(g.SquareSize)*0.12, color.RGBA{200, 30, 30, 255})
+}
+
+// drawHover draws a translucent piece where the human player to move would place their piece.
+func (g *Game) drawHover(board *ebiten.Image) {
+	if g.isReplay || !g.playerToMove().IsHuman() {
+		return
+	}
+	cx, cy, ok := boardCursor()
+	if !ok {
+		return
+	}
+	x, y := cx/(g.SquareSize+g.BorderSize), cy/(g.SquareSize+g.BorderSize)
+	if !g.State().IsLegal(x, y) {
+		return
+	}
+
+	var clr color.Color = color.RGBA{0, 0, 0, 110}
+	if g.State().WhiteToMove {
+		clr = color.RGBA{110, 110, 110, 110}
+	}
+	px, py := g.cellCenter(x, y)
+	ebitenutil.DrawCircle(board, px, py, float64(g.SquareSize/2)*0.8, clr)
+}
+
+// drawStatusBar draws player to move, captures and the last move below the board.
+func (g *Game) drawStatusBar(screen *ebiten.Image) {
+	state := g.State()
+	toMove := "Black"
+	if state.WhiteToMove {
+		toMove = "White"
+	}
+	txt := fmt.Sprintf("%s to move  captures W:%d B:%d", toMove, state.BlackStonesCaptured, state.WhiteStonesCaptured)
+	if len(state.Moves) > 0 {
+		last := state.Moves[len(state.Moves)-1]
+		txt += "  last " + gtp.Vertex(last[0], last[1], g.Dymension)
+	}
+
+	lines := []string{txt}
+	if g.timed() {
+		lines = append(lines, g.clocksLine())
+	}
+
+	face := basicfont.Face7x13
+	top := g.boardSide() + 2*labelMargin
+	for i, line := range lines {
+		text.Draw(screen, line, face, labelMargin, top+i*statusLineHeight+statusLineHeight/2+face.Ascent/2, color.Black)
+	}
+}
+
+// statusBarHeight returns height of the status bar, timed games show clocks on the second line.
+func (g *Game) statusBarHeight() int {
+	if g.timed() {
+		return 2 * statusLineHeight
+	}
+	return statusLineHeight
+}
@@ -0,0 +1,77 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/al-pi314/gogo/clock"
+	"github.com/al-pi314/gogo/rules"
+)
+
+func (g *Game) clockToMove() *clock.Clock {
+	if g.State().WhiteToMove {
+		return g.whiteClock
+	}
+	return g.blackClock
+}
+
+// timed returns wether the game uses time control.
+func (g *Game) timed() bool {
+	return g.TimeControl.System != clock.NoTime
+}
+
+// outOfTime runs the clock of the player to move and ends the game when the player runs out of time. Clock
+// of non human players starts after the move delay.
+func (g *Game) outOfTime() bool {
+	if !g.timed() || time.Now().Before(g.nextMoveAt) {
+		return false
+	}
+	now := time.Now()
+	c := g.clockToMove()
+	c.Start(now)
+	if !c.Expired(now) {
+		return false
+	}
+	g.endByTimeout()
+	return true
+}
+
+// endByTimeout ends the game, the player to move lost on time.
+func (g *Game) endByTimeout() {
+	g.cancelThinking()
+	g.pauseClocks()
+	winner := !g.State().WhiteToMove
+	g.timeline.End(rules.Result{Winner: &winner, Reason: rules.ByTimeout})
+	g.active = false
+	g.startScoring()
+}
+
+// pauseClocks stops both clocks, the clock of the player to move starts again on the next update.
+func (g *Game) pauseClocks() {
+	now := time.Now()
+	g.whiteClock.Pause(now)
+	g.blackClock.Pause(now)
+}
+
+// moveContext returns context of the move, its deadline is the time left to the player to move.
+func (g *Game) moveContext() (context.Context, context.CancelFunc) {
+	c := g.clockToMove()
+	if !g.timed() || !c.Running() {
+		return context.WithCancel(context.Background())
+	}
+	now := time.Now()
+	return context.WithDeadline(context.Background(), now.Add(c.Remaining(now)))
+}
+
+// clocksLine shows time left to both players.
+func (g *Game) clocksLine() string {
+	now := time.Now()
+	white, black := "  ", "  "
+	if g.active && g.State().WhiteToMove {
+		white = "> "
+	} else if g.active {
+		black = "> "
+	}
+	return fmt.Sprintf("%sWhite %s   %sBlack %s", white, g.whiteClock.String(now), black, g.blackClock.String(now))
+}
@@ -1,15 +1,16 @@
 package game
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 	"image/color"
 	"log"
-	"os"
 	"time"
 
 	"github.com/al-pi314/gogo"
+	"github.com/al-pi314/gogo/clock"
 	"github.com/al-pi314/gogo/player"
+	"github.com/al-pi314/gogo/record"
+	"github.com/al-pi314/gogo/rules"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/text"
@@ -17,94 +18,94 @@ import (
 	"golang.org/x/image/font/basicfont"
 )
 
-type Cordinate struct {
-	X int
-	Y int
-}
-
 type Game struct {
 	SaveFileName string
-	saveFile     *os.File
+	WhiteName    string
+	BlackName    string
 	Dymension    int
 	SquareSize   int
 	BorderSize   int
 	WhitePlayer  player.Player
 	BlackPlayer  player.Player
 	MoveDelay    *int
+	Superko      rules.Superko
+	Ruleset      rules.Ruleset
+	Komi         float64
+	Handicap     int
+	FreeHandicap bool
+	MoveLimit    int
+	Heatmap      bool
+	TimeControl  clock.Settings
+
+	active bool
+	// scored is set once dead pieces are confirmed after the game finished
+	scored bool
+	// scoring is copy of the finished game state with marked dead pieces, timeline states are not changed
+	scoring *GameState
+	// dead pieces are estimated on a separate goroutine
+	estimating chan *GameState
+
+	// non human players compute moves on a separate goroutine
+	thinking     chan decision
+	thinkingOn   *GameState
+	stopThinking context.CancelFunc
+	nextMoveAt   time.Time
 
-	active      bool
-	whiteToMove bool
-
-	delay_lock bool
-	locked     Cordinate
-
-	replayMoves   [][2]*int
-	replayMoveIdx int
 	isReplay      bool
-
-	gameState *GameState
+	replayPaused  bool
+	replayDelay   time.Duration
+	replayStepped time.Time
+	replaySeek    string
+	replayResult  *rules.Result
+
+	timeline   *rules.Timeline
+	board      *ebiten.Image
+	whiteClock *clock.Clock
+	blackClock *clock.Clock
+
+	heatmap     [][]float64
+	heatmapPass float64
+	heatmapOn   *GameState
 }
 
 type GameState = gogo.GameState
 
+type decision struct {
+	move player.Move
+	err  error
+}
+
 func NewGame(g Game) *Game {
-	g.gameState = &GameState{
-		Board: make([][]*bool, g.Dymension),
-		Moves: [][2]*int{},
-	}
-	for y := range g.gameState.Board {
-		g.gameState.Board[y] = make([]*bool, g.Dymension)
-	}
-	g.locked = Cordinate{-1, -1}
+	g.timeline = rules.NewTimeline(rules.NewGameState(g.Dymension, rules.Settings{
+		Superko:      g.Superko,
+		Ruleset:      g.Ruleset,
+		Komi:         g.Komi,
+		Handicap:     g.Handicap,
+		FreeHandicap: g.FreeHandicap,
+		MoveLimit:    g.MoveLimit,
+	}))
 	g.active = true
+	g.whiteClock = clock.NewClock(g.TimeControl)
+	g.blackClock = clock.NewClock(g.TimeControl)
 
 	return &g
 }
 
 // ------------------------------------ Helper Functions ------------------------------------ \\
-type GameSave struct {
-	Time  *time.Time
-	Moves [][2]*int
-}
-
 func (g *Game) Save() {
-	if g.saveFile == nil {
-		g.OpenOutputFile(g.SaveFileName)
-	}
-	defer func() {
-		g.saveFile.Close()
-		g.saveFile = nil
-	}()
-
-	now := time.Now()
-	bytes, err := json.Marshal(GameSave{
-		Time:  &now,
-		Moves: g.gameState.Moves,
-	})
-	if err != nil {
-		log.Fatal(errors.Wrap(err, "could not marshal game"))
-	}
-
-	n, err := g.saveFile.Write(bytes)
-	if err != nil || n != len(bytes) {
-		log.Fatal(errors.Wrap(err, fmt.Sprintf("writting error or the write was incomplete (attempted to write %d bytes, written %d bytes)", len(bytes), n)))
-	}
-	fmt.Println("saved game!")
+	r := record.FromState(g.State(), g.WhiteName, g.BlackName)
+	r.TimeControl = g.TimeControl
+	record.Save(g.SaveFileName, r)
 }
 
-func (g *Game) OpenOutputFile(filePath string) {
-	var err error
-	g.saveFile, err = os.OpenFile(filePath, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0755)
-	if err != nil {
-		log.Print(errors.Wrap(err, fmt.Sprintf("failed to open game output file %q", filePath)))
-	} else {
-		g.SaveFileName = filePath
-	}
+// Size returns window size, board is surrounded by cordinate labels and followed by the status bar.
+func (g *Game) Size() (int, int) {
+	side := g.boardSide() + 2*labelMargin
+	return side, side + g.statusBarHeight()
 }
 
-func (g *Game) Size() (int, int) {
-	side := g.Dymension*(g.SquareSize+g.BorderSize) + g.BorderSize
-	return side, side
+func (g *Game) boardSide() int {
+	return g.Dymension*(g.SquareSize+g.BorderSize) + g.BorderSize
 }
 
 // drawSquare draws a square on the image.
@@ -116,310 +117,205 @@ func (g *Game) drawSquare(screen *ebiten.Image, x1, y1, x2, y2 int, clr color.Co
 	}
 }
 
-func (g *Game) pieceAt(x, y int) *bool {
-	if y >= len(g.gameState.Board) || y < 0 || x >= len(g.gameState.Board[y]) || x < 0 {
-		return nil
-	}
-	return g.gameState.Board[y][x]
-}
-
-func (g *Game) hasRoom(x, y int, white bool, checked map[Cordinate]bool, group *[]Cordinate) bool {
-	c := Cordinate{x, y}
-	if chk, ok := checked[c]; ok && chk {
-		return false
-	}
-	checked[c] = true
-
-	if y >= len(g.gameState.Board) || y < 0 || x >= len(g.gameState.Board[y]) || x < 0 {
-		return false
-	}
-
-	if g.gameState.Board[y][x] == nil {
-		return true
-	}
-
-	if *g.gameState.Board[y][x] != white {
-		return false
-	}
-
-	// record group
-	if group != nil {
-		*group = append(*group, c)
-	}
-
-	// execute all checks
-	results := []bool{g.hasRoom(x-1, y, white, checked, group), g.hasRoom(x+1, y, white, checked, group), g.hasRoom(x, y-1, white, checked, group), g.hasRoom(x, y+1, white, checked, group)}
-	for _, r := range results {
-		if r {
-			return true
-		}
-	}
-	return false
-}
-
-func (g *Game) findGroup(x, y int, white bool) []Cordinate {
-	pieceColor := g.pieceAt(x, y)
-	if pieceColor == nil || *pieceColor != white {
-		return nil
-	}
-
-	group := []Cordinate{}
-	if g.hasRoom(x, y, white, map[Cordinate]bool{}, &group) {
-		return nil
-	}
-
-	return group
-}
-
-func updateCtr(whitePtr, blackPtr *int, iswhite bool, cnt int) {
-	if iswhite {
-		blackPtr = whitePtr
-	}
-	*blackPtr += cnt
-}
-
-func (g *Game) asignTeritory(x, y int, checked map[Cordinate]bool) (bool, *bool, int) {
-	c := Cordinate{x, y}
-	if chk, ok := checked[c]; ok && chk {
-		return true, nil, 0
-	}
-	checked[c] = true
-
-	if y < 0 || y >= len(g.gameState.Board) || x < 0 || x >= len(g.gameState.Board[y]) {
-		return true, nil, 0
-	}
-	if g.gameState.Board[y][x] != nil {
-		return true, g.gameState.Board[y][x], 0
-	}
-
-	prev_uniform, prev_owner, prev_cnt := g.asignTeritory(x, y-1, checked)
-	for _, c := range []Cordinate{{x, y + 1}, {x - 1, y}, {x + 1, y}} {
-		curr_uniform, curr_owner, curr_cnt := g.asignTeritory(c.X, c.Y, checked)
-		// previous teritory is not uniform - does not belong to just one player
-		if !prev_uniform {
-			return false, nil, 0
-		}
-
-		// teritories ownership missmatch
-		if prev_owner != nil && curr_owner != nil && *prev_owner != *curr_owner {
-			return false, nil, 0
-		}
-
-		prev_uniform = curr_uniform
-		if curr_owner != nil {
-			prev_owner = curr_owner
-		}
-		prev_cnt += curr_cnt
-	}
-	return prev_uniform, prev_owner, 1 + prev_cnt
-}
-
 // ------------------------------------ ----------------- ------------------------------------ \\
 
 // -------------------------------------- Game Functions ------------------------------------- \\
-func (g *Game) ReplayFromFile(gameFile string) {
-	raw, err := os.ReadFile(gameFile)
-	if err != nil {
-		log.Fatal(errors.Wrap(err, "failed to open game file"))
-	}
-
-	gameSave := GameSave{}
-	if err := json.Unmarshal(raw, &gameSave); err != nil {
-		log.Fatal(errors.Wrap(err, "failed to load game save file"))
+// State returns the current game state. While the finished game is scored it returns the state with marked
+// dead pieces.
+func (g *Game) State() *GameState {
+	if g.scoring != nil {
+		return g.scoring
 	}
-
-	fmt.Printf("...replaying game save from %s\n", gameSave.Time.String())
-	g.replayMoves = gameSave.Moves
-	g.replayMoveIdx = 0
-	g.isReplay = true
-	fmt.Printf("...game lasted %d moves\n", len(g.replayMoves))
+	return g.timeline.State()
 }
 
-func (g *Game) placePiece(x, y int, white bool) bool {
-	// out of bounds or already occupied spaces are invalid
-	if y >= len(g.gameState.Board) || y < 0 || x >= len(g.gameState.Board[y]) || x < 0 || g.gameState.Board[y][x] != nil {
+// Undo takes back the last move. Returns false when there are no moves to take back.
+func (g *Game) Undo() bool {
+	if !g.timeline.Undo() {
 		return false
 	}
-	// place piece
-	g.gameState.Board[y][x] = &white
-	updateCtr(&g.gameState.WhiteStones, &g.gameState.BlackStones, white, 1)
-
-	// check for opponent group eliminations
-	if g.caputreOpponent(x, y, white) {
-		return true
-	}
+	g.active = true
+	g.scored = false
+	g.stopScoring()
+	return true
+}
 
-	// would be eliminated when placed
-	hasRoom := g.hasRoom(x, y, white, map[Cordinate]bool{}, nil)
-	if !hasRoom {
-		g.gameState.Board[y][x] = nil
-		updateCtr(&g.gameState.WhiteStones, &g.gameState.BlackStones, white, -1)
+// Redo replays the last move that was taken back. Returns false when there are no moves to replay.
+func (g *Game) Redo() bool {
+	if !g.timeline.Redo() {
 		return false
 	}
-
+	g.stopScoring()
+	g.active = !g.State().Finished
 	return true
 }
 
-func (g *Game) caputreOpponent(x, y int, white bool) bool {
-	toRemove := []Cordinate{}
-	if g := g.findGroup(x-1, y, !white); g != nil {
-		toRemove = append(toRemove, g...)
-	}
-	if g := g.findGroup(x+1, y, !white); g != nil {
-		toRemove = append(toRemove, g...)
-	}
-	if g := g.findGroup(x, y-1, !white); g != nil {
-		toRemove = append(toRemove, g...)
-	}
-	if g := g.findGroup(x, y+1, !white); g != nil {
-		toRemove = append(toRemove, g...)
+// takeBack takes back or replays moves until a human player is to move so the agent does not immediately
+// replay its move.
+func (g *Game) takeBack(step func() bool) {
+	g.cancelThinking()
+	g.pauseClocks()
+	if !step() || (!g.WhitePlayer.IsHuman() && !g.BlackPlayer.IsHuman()) {
+		return
 	}
-
-	// ko rule
-	if len(toRemove) == 1 {
-		if toRemove[0].X == g.locked.X && toRemove[0].Y == g.locked.Y {
-			return false
-		}
-		g.delay_lock = true
-		g.locked.X = x
-		g.locked.Y = y
+	for !g.playerToMove().IsHuman() && step() {
 	}
+}
 
-	for _, c := range toRemove {
-		g.gameState.Board[c.Y][c.X] = nil
+func (g *Game) playerToMove() player.Player {
+	if g.State().WhiteToMove {
+		return g.WhitePlayer
 	}
-
-	updateCtr(&g.gameState.WhiteStones, &g.gameState.BlackStones, !white, -len(toRemove))
-	updateCtr(&g.gameState.WhiteStonesCaptured, &g.gameState.BlackStonesCaptured, !white, len(toRemove))
-	return len(toRemove) != 0
+	return g.BlackPlayer
 }
 
 // FullMoves returns number of moves and number of moves made by each player
 func (g *Game) FullMoves() (int, int, int) {
-	return g.gameState.MovesCount, g.gameState.WhiteMoves, g.gameState.BlackMoves
+	return g.State().MovesCount, g.State().WhiteMoves, g.State().BlackMoves
 }
 
 // Moves returns number of moves palyed by players.
 func (g *Game) Moves() int {
-	return g.gameState.MovesCount
+	return g.State().MovesCount
 }
 
 // FullScore calculates game score and score of both players.
 func (g *Game) FullScore() (float64, float64, float64) {
-	checked := map[Cordinate]bool{}
-	score := 0.5 + float64(g.gameState.WhiteStones) - float64(g.gameState.WhiteStonesCaptured) - float64(g.gameState.BlackStones) + float64(g.gameState.BlackStonesCaptured)
-	white := float64(g.gameState.BlackStonesCaptured)
-	black := float64(g.gameState.WhiteStonesCaptured)
-	for y := range g.gameState.Board {
-		for x := range g.gameState.Board[y] {
-			if chk, ok := checked[Cordinate{x, y}]; g.gameState.Board[y][x] != nil || (ok && chk) {
-				continue
-			}
-			uniform, owner, size := g.asignTeritory(x, y, checked)
-			if uniform && owner != nil {
-				sign := -1
-				if *owner {
-					white += float64(size)
-					sign = 1
-				} else {
-					black += float64(size)
-					sign = -1
-				}
-				score += float64(sign * size)
-			}
-		}
-	}
-	return score, white, black
+	return g.State().FullScore()
 }
 
 // Score returns game score.
 func (g *Game) Score() float64 {
-	gameScore, _, _ := g.FullScore()
-	return gameScore
+	return g.State().Score()
 }
 
 // -------------------------------------- -------------- ------------------------------------- \\
 
 // --------------------------- Functions required by ebiten engine --------------------------- \\
 func (g *Game) Update() error {
+	if keyPressed(ebiten.KeyH) {
+		g.Heatmap = !g.Heatmap
+	}
+	g.updateHeatmap()
+
+	if g.isReplay {
+		g.updateReplay()
+		return nil
+	}
+
+	switch {
+	case keyPressed(ebiten.KeyZ):
+		g.takeBack(g.Undo)
+		return nil
+	case keyPressed(ebiten.KeyY):
+		g.takeBack(g.Redo)
+		return nil
+	}
+
 	if !g.active {
-		return errors.New("game finished")
+		g.updateScoring()
+		return nil
 	}
 
-	player := g.WhitePlayer
-	opponent := g.BlackPlayer
-	if !g.whiteToMove {
-		player = g.BlackPlayer
-		opponent = g.WhitePlayer
+	if g.outOfTime() {
+		return nil
 	}
 
-	var skip bool
-	var x, y *int
-	if !g.isReplay {
-		skip, x, y = player.Place(g.gameState)
+	p := g.playerToMove()
+	mover := g.clockToMove()
+	var d decision
+	if p.IsHuman() {
+		ctx, cancel := g.moveContext()
+		d.move, d.err = p.Place(ctx, g.State())
+		cancel()
 	} else {
-		if g.replayMoves == nil || g.replayMoveIdx >= len(g.replayMoves) {
-			g.active = false
+		var ready bool
+		if d, ready = g.think(p); !ready {
 			return nil
 		}
-
-		cord := g.replayMoves[g.replayMoveIdx]
-		x = cord[0]
-		y = cord[1]
-		skip = (x == nil && y == nil)
-		g.replayMoveIdx++
+	}
+	switch {
+	case errors.Is(d.err, player.ErrNoMove), errors.Is(d.err, context.DeadlineExceeded):
+		// players out of time lose on the next update
+		return nil
+	case d.err != nil:
+		log.Print(errors.Wrap(d.err, "player failed to make a move, skipping"))
+		d.move = rules.Pass()
 	}
 
-	if skip || ((x != nil && y != nil) && g.placePiece(*x, *y, g.whiteToMove)) {
-		// save move
-		g.gameState.MovesCount++
-		g.gameState.Moves = append(g.gameState.Moves, [2]*int{x, y})
-
-		// consequitive skips end the game
-		if skip && g.gameState.OpponentSkipped {
+	now := time.Now()
+	if g.timeline.Apply(d.move) {
+		// moves made after the player ran out of time are taken back and the player loses on time
+		if !mover.Stop(now) {
+			g.timeline.Undo()
+			g.endByTimeout()
+			return nil
+		}
+		if g.State().Finished {
 			g.active = false
+			g.startScoring()
 		}
-		g.gameState.OpponentSkipped = false
-		if skip {
-			g.gameState.OpponentSkipped = true
-			if g.whiteToMove {
-				g.gameState.WhiteMoves += 1
-			} else {
-				g.gameState.BlackMoves += 1
-			}
+		// pace moves made by non human players
+		if !g.playerToMove().IsHuman() && g.MoveDelay != nil {
+			g.nextMoveAt = time.Now().Add(time.Duration(*g.MoveDelay) * time.Millisecond)
 		}
+	}
+	return nil
+}
 
-		// lock unlocks after next successful move
-		if !g.delay_lock {
-			g.locked.X = -1
-			g.locked.Y = -1
+// think starts computing the player move on a separate goroutine and returns the move once it is ready, so
+// the window stays responsive while the player is thinking.
+func (g *Game) think(p player.Player) (decision, bool) {
+	if g.thinking == nil {
+		if time.Now().Before(g.nextMoveAt) {
+			return decision{}, false
 		}
-		g.delay_lock = false
-		// change player to move
-		g.whiteToMove = !g.whiteToMove
-		if (g.isReplay || !opponent.IsHuman()) && g.MoveDelay != nil {
-			time.Sleep(time.Duration(*g.MoveDelay) * time.Millisecond)
+
+		// player works on a copy so drawing and undo do not race with it
+		var ctx context.Context
+		ctx, g.stopThinking = g.moveContext()
+		g.thinkingOn = g.State().Clone()
+		g.thinking = make(chan decision, 1)
+		go func(state *GameState, result chan<- decision) {
+			d := decision{}
+			d.move, d.err = p.Place(ctx, state)
+			result <- d
+		}(g.thinkingOn, g.thinking)
+		return decision{}, false
+	}
+
+	select {
+	case d := <-g.thinking:
+		thinkingOn := g.thinkingOn
+		g.cancelThinking()
+		// moves were taken back or replayed while the player was thinking
+		if errors.Is(d.err, context.Canceled) || thinkingOn.Hash != g.State().Hash || thinkingOn.MovesCount != g.State().MovesCount {
+			return decision{}, false
 		}
+		return d, true
+	default:
+		return decision{}, false
 	}
-	return nil
+}
+
+// cancelThinking stops the player that is thinking, its move is never read.
+func (g *Game) cancelThinking() {
+	if g.stopThinking != nil {
+		g.stopThinking()
+	}
+	g.thinking = nil
+	g.thinkingOn = nil
+	g.stopThinking = nil
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
-	// end screen
-	if !g.active {
-		screen.Fill(color.White)
-		score := g.Score()
-		winner := "Black"
-		if score >= 0.0 {
-			winner = "White"
-		}
-		face := basicfont.Face7x13
-		txt := fmt.Sprintf("%s player won! Score: %.2f", winner, score)
-		centerX := 0.5*float64(g.Dymension*(g.SquareSize+g.BorderSize)+g.BorderSize) - float64(face.Width*len(txt))/2
-		centerY := 0.5 * float64(g.Dymension*(g.SquareSize+g.BorderSize)+g.BorderSize)
-		text.Draw(screen, txt, face, int(centerX), int(centerY), color.Black)
-		return
+	// board is drawn on its own image and placed inside of the labels
+	side := g.boardSide()
+	if g.board == nil || g.board.Bounds().Dx() != side {
+		g.board = ebiten.NewImage(side, side)
 	}
+	board := g.board
+	board.Clear()
 
 	// draw board - squares with left and top borders
 	for x := 0; x <= g.Dymension+1; x++ {
@@ -430,13 +326,13 @@ func (g *Game) Draw(screen *ebiten.Image) {
 			if y <= g.Dymension {
 				x2 := x1 + g.BorderSize
 				y2 := y1 + g.SquareSize + g.BorderSize
-				g.drawSquare(screen, x1, y1, x2, y2, color.RGBA{160, 175, 190, 1})
+				g.drawSquare(board, x1, y1, x2, y2, color.RGBA{160, 175, 190, 1})
 			}
 			// draw top border
 			if x <= g.Dymension {
 				x2 := x1 + g.SquareSize + g.BorderSize
 				y2 := y1 + g.BorderSize
-				g.drawSquare(screen, x1, y1, x2, y2, color.RGBA{160, 175, 190, 1})
+				g.drawSquare(board, x1, y1, x2, y2, color.RGBA{160, 175, 190, 1})
 			}
 
 			// draw empty square when inside the board
@@ -445,27 +341,63 @@ func (g *Game) Draw(screen *ebiten.Image) {
 				y1 += g.BorderSize
 				x2 := x1 + g.SquareSize
 				y2 := y1 + g.SquareSize
-				g.drawSquare(screen, x1, y1, x2, y2, color.RGBA{180, 90, 30, 1})
+				g.drawSquare(board, x1, y1, x2, y2, color.RGBA{180, 90, 30, 1})
 			}
 		}
 	}
 
+	g.drawHeatmap(board)
+
 	// draw pieces
-	for piece_y, row := range g.gameState.Board {
+	for piece_y, row := range g.State().Board {
 		for piece_x, piece := range row {
 			if piece == nil {
 				continue
 			}
 			x := (piece_x+1)*(g.SquareSize+g.BorderSize) - g.SquareSize/2
 			y := (piece_y+1)*(g.SquareSize+g.BorderSize) - g.SquareSize/2
-			clr := color.White
+			var clr color.Color = color.White
 			if !*piece {
 				clr = color.Black
 			}
-			ebitenutil.DrawCircle(screen, float64(x), float64(y), float64(g.SquareSize/2)*0.8, clr)
+			// dead pieces are see-through
+			if g.State().IsDead(piece_x, piece_y) {
+				clr = color.RGBA{0, 0, 0, 90}
+				if *piece {
+					clr = color.RGBA{90, 90, 90, 90}
+				}
+			}
+			ebitenutil.DrawCircle(board, float64(x), float64(y), float64(g.SquareSize/2)*0.8, clr)
 		}
 
 	}
+
+	g.drawLastMove(board)
+	g.drawHover(board)
+	if !g.active {
+		g.drawScoring(board, g.State().Result())
+	}
+	if g.isReplay {
+		if result, ok := g.replayEnd(); ok {
+			g.drawScoring(board, result)
+		}
+		g.drawReplayOverlay(board)
+	}
+	if g.thinking != nil {
+		face := basicfont.Face7x13
+		txt := "thinking..."
+		g.drawSquare(board, g.BorderSize, g.BorderSize, g.BorderSize+face.Width*len(txt)+4, g.BorderSize+face.Height+4, color.White)
+		text.Draw(board, txt, face, g.BorderSize+2, g.BorderSize+face.Ascent+2, color.Black)
+	}
+
+	screen.Fill(color.RGBA{160, 175, 190, 255})
+	op := &ebiten.DrawImageOptions{}
+	// board pixels are copied as they are, same as when they were drawn directly on the screen
+	op.CompositeMode = ebiten.CompositeModeCopy
+	op.GeoM.Translate(labelMargin, labelMargin)
+	screen.DrawImage(board, op)
+	g.drawLabels(screen)
+	g.drawStatusBar(screen)
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
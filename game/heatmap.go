@@ -0,0 +1,95 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/al-pi314/gogo/player"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font/basicfont"
+)
+
+// analyser returns the player whose policy is shown. Player to move is preferred, on human turns the
+// opponent agent is asked what it would play.
+func (g *Game) analyser() player.Analyser {
+	if a, ok := g.playerToMove().(player.Analyser); ok {
+		return a
+	}
+	opponent := g.WhitePlayer
+	if g.State().WhiteToMove {
+		opponent = g.BlackPlayer
+	}
+	if a, ok := opponent.(player.Analyser); ok {
+		return a
+	}
+	return nil
+}
+
+// updateHeatmap recomputes the policy when the position changed.
+func (g *Game) updateHeatmap() {
+	if !g.Heatmap {
+		return
+	}
+	state := g.State()
+	if g.heatmapOn == state {
+		return
+	}
+	g.heatmapOn = state
+	g.heatmap = nil
+
+	analyser := g.analyser()
+	if analyser == nil {
+		return
+	}
+	g.heatmap, g.heatmapPass = analyser.Policy(state)
+}
+
+// drawHeatmap colors empty positions by network effectivness, the most effective positions are the
+// brightest.
+func (g *Game) drawHeatmap(board *ebiten.Image) {
+	if !g.Heatmap || g.heatmap == nil || g.heatmapOn != g.State() {
+		return
+	}
+
+	// effectivness is scaled to the range of the current output
+	min, max := 1.0, 0.0
+	for _, row := range g.heatmap {
+		for _, v := range row {
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+	}
+	spread := max - min
+	if spread == 0 {
+		spread = 1
+	}
+
+	face := basicfont.Face7x13
+	state := g.State()
+	for y, row := range g.heatmap {
+		for x, v := range row {
+			if state.PieceAt(x, y) != nil {
+				continue
+			}
+			alpha := uint8(40 + 180*(v-min)/spread)
+			x1 := float64(x*(g.SquareSize+g.BorderSize) + g.BorderSize)
+			y1 := float64(y*(g.SquareSize+g.BorderSize) + g.BorderSize)
+			ebitenutil.DrawRect(board, x1, y1, float64(g.SquareSize), float64(g.SquareSize), color.RGBA{alpha, alpha / 4, 0, alpha})
+
+			txt := fmt.Sprintf("%.2f", v)
+			cx, cy := g.cellCenter(x, y)
+			text.Draw(board, txt, face, int(cx)-face.Width*len(txt)/2, int(cy)+face.Ascent/2, color.White)
+		}
+	}
+
+	txt := fmt.Sprintf("pass %.2f", g.heatmapPass)
+	bottom := g.boardSide() - g.BorderSize
+	g.drawSquare(board, g.BorderSize, bottom-face.Height-4, g.BorderSize+face.Width*len(txt)+4, bottom, color.White)
+	text.Draw(board, txt, face, g.BorderSize+2, bottom-face.Height-2+face.Ascent, color.Black)
+}
@@ -0,0 +1,37 @@
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Input reads human player actions from the ebiten window. Click positions are relative to the board.
+type Input struct{}
+
+func (Input) Click() (int, int, bool) {
+	if !ebiten.IsFocused() || !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return 0, 0, false
+	}
+	return boardCursor()
+}
+
+func (Input) Skip() bool {
+	return ebiten.IsFocused() && inpututil.IsKeyJustPressed(ebiten.KeySpace)
+}
+
+func (Input) Resign() bool {
+	return ebiten.IsFocused() && inpututil.IsKeyJustPressed(ebiten.KeyR)
+}
+
+func keyPressed(key ebiten.Key) bool {
+	return ebiten.IsFocused() && inpututil.IsKeyJustPressed(key)
+}
+
+// boardCursor returns cursor position relative to the board. Returns false when the cursor is outside of the
+// board area.
+func boardCursor() (int, int, bool) {
+	x, y := ebiten.CursorPosition()
+	x -= labelMargin
+	y -= labelMargin
+	return x, y, x >= 0 && y >= 0
+}
@@ -0,0 +1,149 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"time"
+
+	"github.com/al-pi314/gogo/record"
+	"github.com/al-pi314/gogo/rules"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font/basicfont"
+)
+
+const defaultReplayDelay = 500 * time.Millisecond
+
+func (g *Game) ReplayFromFile(gameFile string) {
+	gameSave := record.Load(gameFile)
+
+	if gameSave.Time != nil {
+		fmt.Printf("...replaying game save from %s\n", gameSave.Time.String())
+	}
+	// legacy saves do not record board settings
+	if gameSave.Dymension > 0 {
+		settings := gameSave.Settings()
+		settings.Superko = g.Superko
+		g.Dymension = gameSave.Dymension
+		g.timeline = rules.NewTimeline(rules.NewGameState(g.Dymension, settings))
+	}
+	if gameSave.WhiteName != "" || gameSave.BlackName != "" {
+		fmt.Printf("...white player %q, black player %q\n", gameSave.WhiteName, gameSave.BlackName)
+	}
+
+	// moves are played in advance so the replay can be navigated
+	illegal := 0
+	for i, m := range gameSave.Moves {
+		move := rules.Pass()
+		if m[0] != nil && m[1] != nil {
+			move = rules.Play(*m[0], *m[1])
+		}
+		// legacy saves do not record colors, players are alternated
+		white := g.timeline.State().WhiteToMove
+		if i < len(gameSave.MoveColors) {
+			white = gameSave.MoveColors[i]
+		}
+		if !g.timeline.ApplyAs(move, white) {
+			illegal++
+		}
+	}
+	if illegal > 0 {
+		fmt.Printf("WARRNING: %d illegal moves were left out of the replay\n", illegal)
+	}
+	// recorded result is shown at the last position, games that were not counted do not end on the board
+	g.replayResult = gameSave.Result
+	if gameSave.Result != nil {
+		fmt.Printf("...game result %s\n", gameSave.Result)
+	}
+	g.timeline.Seek(0)
+
+	g.isReplay = true
+	g.replayDelay = defaultReplayDelay
+	if g.MoveDelay != nil && *g.MoveDelay > 0 {
+		g.replayDelay = time.Duration(*g.MoveDelay) * time.Millisecond
+	}
+	fmt.Printf("...game lasted %d moves\n", g.timeline.Len())
+}
+
+// updateReplay handles replay controls and steps through moves while the replay is playing.
+func (g *Game) updateReplay() {
+	switch {
+	case keyPressed(ebiten.KeySpace):
+		g.replayPaused = !g.replayPaused
+		g.replayStepped = time.Now()
+	case keyPressed(ebiten.KeyArrowRight):
+		g.replayPaused = true
+		g.timeline.Redo()
+	case keyPressed(ebiten.KeyArrowLeft):
+		g.replayPaused = true
+		g.timeline.Undo()
+	case keyPressed(ebiten.KeyHome):
+		g.timeline.Seek(0)
+	case keyPressed(ebiten.KeyEnd):
+		g.timeline.Seek(g.timeline.Len())
+	case keyPressed(ebiten.KeyArrowUp):
+		g.replayDelay = g.replayDelay / 2
+		if g.replayDelay < 10*time.Millisecond {
+			g.replayDelay = 10 * time.Millisecond
+		}
+	case keyPressed(ebiten.KeyArrowDown):
+		g.replayDelay = g.replayDelay * 2
+	case keyPressed(ebiten.KeyBackspace) && len(g.replaySeek) > 0:
+		g.replaySeek = g.replaySeek[:len(g.replaySeek)-1]
+	case keyPressed(ebiten.KeyEnter):
+		// jump to the typed move number
+		if move, err := strconv.Atoi(g.replaySeek); err == nil {
+			if move > g.timeline.Len() {
+				move = g.timeline.Len()
+			}
+			g.timeline.Seek(move)
+			g.replayPaused = true
+		}
+		g.replaySeek = ""
+	}
+	for digit := ebiten.KeyDigit0; digit <= ebiten.KeyDigit9; digit++ {
+		if keyPressed(digit) {
+			g.replaySeek += strconv.Itoa(int(digit - ebiten.KeyDigit0))
+		}
+	}
+
+	if g.replayPaused || time.Since(g.replayStepped) < g.replayDelay {
+		return
+	}
+	g.replayStepped = time.Now()
+	if !g.timeline.Redo() {
+		g.replayPaused = true
+	}
+}
+
+// replayEnd returns result of the replayed game when the replay is at the last position. Saves without the
+// result use result of the final position when the game finished on the board.
+func (g *Game) replayEnd() (rules.Result, bool) {
+	if g.timeline.Current() != g.timeline.Len() {
+		return rules.Result{}, false
+	}
+	if g.replayResult != nil {
+		return *g.replayResult, true
+	}
+	if g.State().Finished {
+		return g.State().Result(), true
+	}
+	return rules.Result{}, false
+}
+
+// drawReplayOverlay draws move counter and replay status.
+func (g *Game) drawReplayOverlay(screen *ebiten.Image) {
+	status := "playing"
+	if g.replayPaused {
+		status = "paused"
+	}
+	txt := fmt.Sprintf("move %d/%d  %s  %.2fs/move", g.timeline.Current(), g.timeline.Len(), status, g.replayDelay.Seconds())
+	if g.replaySeek != "" {
+		txt += fmt.Sprintf("  jump to %s", g.replaySeek)
+	}
+
+	face := basicfont.Face7x13
+	g.drawSquare(screen, g.BorderSize, g.BorderSize, g.BorderSize+face.Width*len(txt)+4, g.BorderSize+face.Height+4, color.White)
+	text.Draw(screen, txt, face, g.BorderSize+2, g.BorderSize+face.Ascent+2, color.Black)
+}
@@ -0,0 +1,169 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/al-pi314/gogo/rules"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font/basicfont"
+)
+
+// startScoring estimates dead pieces of the finished game. Games without human players are scored once the
+// estimate is ready, humans can correct the estimate first. Resigned games are not counted.
+func (g *Game) startScoring() {
+	g.stopScoring()
+	if !g.counted() {
+		g.confirmScore()
+		return
+	}
+	g.estimateDead()
+}
+
+// estimateDead starts estimating dead pieces on a copy of the finished game state so the window stays
+// responsive, the estimate is read by updateScoring.
+func (g *Game) estimateDead() {
+	estimating := make(chan *GameState, 1)
+	g.estimating = estimating
+	go func(state *GameState) {
+		state.EstimateDead()
+		estimating <- state
+	}(g.timeline.State().Clone())
+}
+
+// stopScoring drops marked dead pieces and the running estimate, its result is never read.
+func (g *Game) stopScoring() {
+	g.scoring = nil
+	g.estimating = nil
+}
+
+// counted returns wether the game result depends on the score.
+func (g *Game) counted() bool {
+	return counted(g.State().Result())
+}
+
+func counted(result rules.Result) bool {
+	return result.Reason == rules.ByScore || result.Reason == rules.ByMoveLimit
+}
+
+// confirmScore accepts marked dead pieces and saves the game.
+func (g *Game) confirmScore() {
+	g.scored = true
+	if g.SaveFileName != "" {
+		g.Save()
+	}
+}
+
+// updateScoring lets human players mark dead groups by clicking on them and confirm the score with enter.
+func (g *Game) updateScoring() {
+	if g.scored {
+		return
+	}
+	if g.estimating != nil {
+		select {
+		case state := <-g.estimating:
+			g.estimating = nil
+			g.scoring = state
+			if !g.WhitePlayer.IsHuman() && !g.BlackPlayer.IsHuman() {
+				g.confirmScore()
+			}
+		default:
+		}
+		return
+	}
+
+	switch {
+	case keyPressed(ebiten.KeyEnter):
+		g.confirmScore()
+		return
+	case keyPressed(ebiten.KeyE):
+		g.estimateDead()
+		return
+	}
+	if x, y, ok := (Input{}).Click(); ok && g.counted() {
+		if g.scoring == nil {
+			g.scoring = g.timeline.State().Clone()
+		}
+		g.scoring.ToggleDead(x/(g.SquareSize+g.BorderSize), y/(g.SquareSize+g.BorderSize))
+	}
+}
+
+// drawScoring marks territory of both players and shows the result with score breakdown over the final position.
+func (g *Game) drawScoring(board *ebiten.Image, result rules.Result) {
+	state := g.State()
+	lines := resultLines(result)
+	if !counted(result) {
+		g.drawTextBox(board, lines)
+		return
+	}
+
+	size := float64(g.SquareSize) * 0.3
+	for y, row := range state.Territory() {
+		for x, owner := range row {
+			if owner == nil {
+				continue
+			}
+			clr := color.Black
+			if *owner {
+				clr = color.White
+			}
+			cx, cy := g.cellCenter(x, y)
+			ebitenutil.DrawRect(board, cx-size/2, cy-size/2, size, size, clr)
+		}
+	}
+
+	white, black := state.ScoreBreakdown()
+	lines = append(lines,
+		fmt.Sprintf("White: %d stones, %d territory, %d prisoners, %.1f komi", white.Stones, white.Territory, white.Prisoners, white.Komi),
+		fmt.Sprintf("Black: %d stones, %d territory, %d prisoners, %.1f komi", black.Stones, black.Territory, black.Prisoners, black.Komi),
+	)
+	switch {
+	case g.isReplay:
+		// replays only show the result
+	case g.estimating != nil:
+		lines = append(lines, "estimating dead groups...")
+	case !g.scored:
+		lines = append(lines, "click dead groups, E to estimate, enter to confirm")
+	}
+	g.drawTextBox(board, lines)
+}
+
+// resultLines describes the game result.
+func resultLines(result rules.Result) []string {
+	if result.Winner == nil {
+		return []string{fmt.Sprintf("Draw (%s)", result.Reason)}
+	}
+	winner := "Black"
+	if *result.Winner {
+		winner = "White"
+	}
+	switch result.Reason {
+	case rules.ByResign:
+		return []string{fmt.Sprintf("%s player won by resignation!", winner)}
+	case rules.ByTimeout:
+		return []string{fmt.Sprintf("%s player won on time!", winner)}
+	case rules.ByMoveLimit:
+		return []string{fmt.Sprintf("%s player won by %.1f points (move limit reached)!", winner, result.Margin)}
+	}
+	return []string{fmt.Sprintf("%s player won by %.1f points!", winner, result.Margin)}
+}
+
+// drawTextBox draws lines of text in a box in the middle of the board.
+func (g *Game) drawTextBox(board *ebiten.Image, lines []string) {
+	face := basicfont.Face7x13
+	width := 0
+	for _, txt := range lines {
+		if face.Width*len(txt) > width {
+			width = face.Width * len(txt)
+		}
+	}
+	height := len(lines) * (face.Height + 4)
+	x1 := (g.boardSide() - width) / 2
+	y1 := (g.boardSide() - height) / 2
+	ebitenutil.DrawRect(board, float64(x1-4), float64(y1-4), float64(width+8), float64(height+8), color.RGBA{230, 230, 230, 230})
+	for i, txt := range lines {
+		text.Draw(board, txt, face, x1, y1+i*(face.Height+4)+face.Ascent, color.Black)
+	}
+}
@@ -0,0 +1,267 @@
+package gtp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/al-pi314/gogo/rules"
+	"github.com/pkg/errors"
+)
+
+// Player generates moves for the engine. It is satisfied by players from the player package.
+type Player interface {
+	Place(context.Context, *rules.GameState) (rules.Move, error)
+}
+
+type Engine struct {
+	Name     string
+	Version  string
+	Player   Player
+	Settings rules.Settings
+	// Dymension is the starting board size. Board size can not be changed when FixedDymension is set.
+	Dymension      int
+	FixedDymension bool
+
+	state    *rules.GameState
+	handlers map[string]func([]string) (string, error)
+	quit     bool
+}
+
+func NewEngine(e Engine) *Engine {
+	e.state = rules.NewGameState(e.Dymension, e.Settings)
+	e.handlers = map[string]func([]string) (string, error){
+		"protocol_version":  func([]string) (string, error) { return "2", nil },
+		"name":              func([]string) (string, error) { return e.Name, nil },
+		"version":           func([]string) (string, error) { return e.Version, nil },
+		"known_command":     e.knownCommand,
+		"list_commands":     e.listCommands,
+		"quit":              e.quitCommand,
+		"boardsize":         e.boardsize,
+		"clear_board":       e.clearBoard,
+		"komi":              e.komi,
+		"set_free_handicap": e.setFreeHandicap,
+		"play":              e.play,
+		"genmove":           e.genmove,
+		"final_score":       e.finalScore,
+		"showboard":         e.showboard,
+	}
+	return &e
+}
+
+// Run reads commands from the input and writes responses to the output until quit command or end of input.
+func (e *Engine) Run(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	for !e.quit && scanner.Scan() {
+		c := ParseCommand(scanner.Text())
+		if c == nil {
+			continue
+		}
+
+		response, err := e.Execute(c)
+		status := "="
+		if err != nil {
+			status = "?"
+			response = err.Error()
+		}
+		if response != "" {
+			response = " " + response
+		}
+		if _, err := fmt.Fprintf(out, "%s%s%s\n\n", status, c.ID, response); err != nil {
+			return errors.Wrap(err, "failed to write gtp response")
+		}
+	}
+	return scanner.Err()
+}
+
+// Execute executes a single command and returns its response.
+func (e *Engine) Execute(c *Command) (string, error) {
+	handler, ok := e.handlers[c.Name]
+	if !ok {
+		return "", errors.New("unknown command")
+	}
+	return handler(c.Args)
+}
+
+// State returns the current game state.
+func (e *Engine) State() *rules.GameState {
+	return e.state
+}
+
+// ------------------------------------ Command Handlers ------------------------------------ \\
+func (e *Engine) knownCommand(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("syntax error")
+	}
+	_, ok := e.handlers[strings.ToLower(args[0])]
+	return strconv.FormatBool(ok), nil
+}
+
+func (e *Engine) listCommands([]string) (string, error) {
+	names := []string{}
+	for name := range e.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, "\n"), nil
+}
+
+func (e *Engine) quitCommand([]string) (string, error) {
+	e.quit = true
+	return "", nil
+}
+
+func (e *Engine) boardsize(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("syntax error")
+	}
+	size, err := strconv.Atoi(args[0])
+	if err != nil {
+		return "", errors.New("syntax error")
+	}
+	if size < 2 || size > len(columns) || (e.FixedDymension && size != e.Dymension) {
+		return "", errors.New("unacceptable size")
+	}
+	e.Dymension = size
+	return e.clearBoard(nil)
+}
+
+func (e *Engine) clearBoard([]string) (string, error) {
+	e.state = rules.NewGameState(e.Dymension, e.Settings)
+	return "", nil
+}
+
+func (e *Engine) komi(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("syntax error")
+	}
+	komi, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return "", errors.New("syntax error")
+	}
+	e.Settings.Komi = komi
+	e.state.Settings.Komi = komi
+	return "", nil
+}
+
+// prepareMove sets the player to move. Controller decides when the game ends so finished games are resumed.
+func (e *Engine) prepareMove(color string) error {
+	white, err := ParseColor(color)
+	if err != nil {
+		return errors.New("syntax error")
+	}
+	e.state.WhiteToMove = white
+	e.state.Finished = false
+	return nil
+}
+
+func (e *Engine) setFreeHandicap(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", errors.New("syntax error")
+	}
+	if len(e.state.Moves) > 0 || e.state.BlackStones+e.state.WhiteStones > 0 {
+		return "", errors.New("board not empty")
+	}
+
+	settings := e.Settings
+	settings.HandicapSetup = []rules.Cordinate{}
+	for _, vertex := range args {
+		x, y, err := ParseVertex(vertex, e.Dymension)
+		if err != nil || x == nil || y == nil {
+			return "", errors.New("syntax error")
+		}
+		settings.HandicapSetup = append(settings.HandicapSetup, rules.Cordinate{X: *x, Y: *y})
+	}
+	settings.Handicap = len(settings.HandicapSetup)
+	e.state = rules.NewGameState(e.Dymension, settings)
+	return "", nil
+}
+
+func (e *Engine) play(args []string) (string, error) {
+	if len(args) != 2 {
+		return "", errors.New("syntax error")
+	}
+	if err := e.prepareMove(args[0]); err != nil {
+		return "", err
+	}
+	x, y, err := ParseVertex(args[1], e.Dymension)
+	if err != nil {
+		return "", errors.New("syntax error")
+	}
+
+	if x == nil || y == nil {
+		e.state.Skip()
+		return "", nil
+	}
+	if !e.state.Place(*x, *y) {
+		return "", errors.New("illegal move")
+	}
+	return "", nil
+}
+
+func (e *Engine) genmove(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("syntax error")
+	}
+	if err := e.prepareMove(args[0]); err != nil {
+		return "", err
+	}
+	if e.Player == nil {
+		return "", errors.New("engine has no player")
+	}
+	// players suggest next best move when their previous suggestion was illegal
+	for attempt := 0; attempt <= e.Dymension*e.Dymension; attempt++ {
+		m, err := e.Player.Place(context.Background(), e.state)
+		if err != nil {
+			return "", errors.Wrap(err, "player failed to generate move")
+		}
+		switch m.Kind {
+		case rules.ResignMove:
+			// game is left as it is, controller decides how to end it
+			return "resign", nil
+		case rules.PlayMove:
+			if e.state.Place(m.X, m.Y) {
+				return Vertex(&m.X, &m.Y, e.Dymension), nil
+			}
+			continue
+		}
+		break
+	}
+	e.state.Skip()
+	return "pass", nil
+}
+
+func (e *Engine) finalScore([]string) (string, error) {
+	return e.state.ResultString(), nil
+}
+
+func (e *Engine) showboard([]string) (string, error) {
+	b := strings.Builder{}
+	header := "  "
+	for x := 0; x < e.Dymension; x++ {
+		header += " " + string(columns[x])
+	}
+	b.WriteString(header)
+	for y, row := range e.state.Board {
+		fmt.Fprintf(&b, "\n%2d", e.Dymension-y)
+		for _, piece := range row {
+			switch {
+			case piece == nil:
+				b.WriteString(" .")
+			case *piece:
+				b.WriteString(" O")
+			default:
+				b.WriteString(" X")
+			}
+		}
+		fmt.Fprintf(&b, " %d", e.Dymension-y)
+	}
+	fmt.Fprintf(&b, "\n%s\nWHITE (O) captured %d, BLACK (X) captured %d", header, e.state.BlackStonesCaptured, e.state.WhiteStonesCaptured)
+	return b.String(), nil
+}
+
+// ------------------------------------ ---------------- ------------------------------------ \\
@@ -0,0 +1,108 @@
+package gtp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/al-pi314/gogo/rules"
+)
+
+func TestEngine(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine Engine
+		// commands are written one per line, responses are separated by empty lines
+		commands  []string
+		responses []string
+	}{
+		{
+			name:      "command ids",
+			commands:  []string{"1 name", "2 protocol_version", "3 unknown", "# comment", "4 known_command genmove"},
+			responses: []string{"=1 gogo", "=2 2", "?3 unknown command", "=4 true"},
+		},
+		{
+			name:      "boardsize",
+			commands:  []string{"boardsize 7", "play b G7", "boardsize 1", "boardsize 26", "boardsize seven"},
+			responses: []string{"=", "=", "? unacceptable size", "? unacceptable size", "? syntax error"},
+		},
+		{
+			name:      "fixed boardsize",
+			engine:    Engine{FixedDymension: true},
+			commands:  []string{"boardsize 5", "boardsize 9"},
+			responses: []string{"=", "? unacceptable size"},
+		},
+		{
+			name:      "boardsize clears the board",
+			commands:  []string{"play b C3", "boardsize 5", "play w C3"},
+			responses: []string{"=", "=", "="},
+		},
+		{
+			name:      "play",
+			commands:  []string{"play b C3", "play w C3", "play w pass", "play b Z9", "play red C2", "play b"},
+			responses: []string{"=", "? illegal move", "=", "? syntax error", "? syntax error", "? syntax error"},
+		},
+		{
+			name:      "play suicide",
+			commands:  []string{"play b A4", "play b B5", "play w A5"},
+			responses: []string{"=", "=", "? illegal move"},
+		},
+		{
+			name:      "genmove",
+			commands:  []string{"genmove b", "genmove w", "play b A4", "genmove w"},
+			responses: []string{"= A5", "= B5", "=", "= C5"},
+		},
+		{
+			name:      "genmove pass without legal moves",
+			commands:  []string{"boardsize 2", "play b A2", "play b B1", "genmove w"},
+			responses: []string{"=", "=", "=", "= pass"},
+		},
+		{
+			name:      "final_score area counting",
+			engine:    Engine{Settings: rules.Settings{Komi: 0.5}},
+			commands:  []string{"play b C3", "final_score"},
+			responses: []string{"=", "= B+24.5"},
+		},
+		{
+			name:      "final_score komi",
+			commands:  []string{"komi 6.5", "final_score", "komi x"},
+			responses: []string{"=", "= W+6.5", "? syntax error"},
+		},
+		{
+			name:      "final_score draw",
+			commands:  []string{"final_score"},
+			responses: []string{"= 0"},
+		},
+		{
+			name:      "final_score territory counting",
+			engine:    Engine{Settings: rules.Settings{Ruleset: rules.JapaneseRules, Komi: 0.5}},
+			commands:  []string{"play b C3", "final_score"},
+			responses: []string{"=", "= B+23.5"},
+		},
+		{
+			name:      "quit",
+			commands:  []string{"quit", "name"},
+			responses: []string{"="},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := test.engine
+			e.Name = "gogo"
+			e.Player = &FakePlayer{}
+			if e.Dymension == 0 {
+				e.Dymension = 5
+			}
+
+			out := bytes.Buffer{}
+			if err := NewEngine(e).Run(strings.NewReader(strings.Join(test.commands, "\n")), &out); err != nil {
+				t.Fatalf("engine failed: %v", err)
+			}
+			expected := strings.Join(test.responses, "\n\n") + "\n\n"
+			if out.String() != expected {
+				t.Fatalf("responses %q, expected %q", out.String(), expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package gtp
+
+import (
+	"context"
+
+	"github.com/al-pi314/gogo/rules"
+)
+
+// FakePlayer plays the first legal position in reading order. It makes engine runs deterministic and does not
+// require a trained population.
+type FakePlayer struct{}
+
+func (p *FakePlayer) Place(ctx context.Context, state *rules.GameState) (rules.Move, error) {
+	if err := ctx.Err(); err != nil {
+		return rules.Move{}, err
+	}
+	moves := state.LegalMoves()
+	if len(moves) == 0 {
+		return rules.Pass(), nil
+	}
+	return rules.Play(moves[0].X, moves[0].Y), nil
+}
@@ -0,0 +1,97 @@
+package gtp
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// columns used by GTP vertices, letter I is skipped
+const columns = "ABCDEFGHJKLMNOPQRSTUVWXYZ"
+
+// Command is a single GTP command.
+type Command struct {
+	ID   string
+	Name string
+	Args []string
+}
+
+// Column returns GTP column letter of the board column.
+func Column(x int) string {
+	return string(columns[x])
+}
+
+// Vertex returns GTP vertex of the board position (A1 is bottom left). Nil cordinates represent a pass.
+func Vertex(x, y *int, dymension int) string {
+	if x == nil || y == nil {
+		return "pass"
+	}
+	return Column(*x) + strconv.Itoa(dymension-*y)
+}
+
+// ParseVertex returns board position of the GTP vertex. Nil cordinates represent a pass.
+func ParseVertex(vertex string, dymension int) (*int, *int, error) {
+	vertex = strings.ToUpper(vertex)
+	if vertex == "PASS" {
+		return nil, nil, nil
+	}
+	if len(vertex) < 2 {
+		return nil, nil, errors.Errorf("invalid vertex %q", vertex)
+	}
+
+	x := strings.IndexByte(columns, vertex[0])
+	row, err := strconv.Atoi(vertex[1:])
+	if x < 0 || err != nil {
+		return nil, nil, errors.Errorf("invalid vertex %q", vertex)
+	}
+	y := dymension - row
+	if x >= dymension || y < 0 || y >= dymension {
+		return nil, nil, errors.Errorf("vertex %q is outside of the board", vertex)
+	}
+	return &x, &y, nil
+}
+
+// ParseColor returns true for white and false for black color arguments.
+func ParseColor(color string) (bool, error) {
+	switch strings.ToLower(color) {
+	case "w", "white":
+		return true, nil
+	case "b", "black":
+		return false, nil
+	}
+	return false, errors.Errorf("invalid color %q", color)
+}
+
+// ParseCommand parses a line of GTP input. Returns nil for empty and comment lines.
+func ParseCommand(line string) *Command {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+	line = strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return ' '
+		}
+		if r < 32 || r == 127 {
+			return -1
+		}
+		return r
+	}, line)
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	c := Command{}
+	if _, err := strconv.Atoi(fields[0]); err == nil {
+		c.ID = fields[0]
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	c.Name = strings.ToLower(fields[0])
+	c.Args = fields[1:]
+	return &c
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"math"
 	"math/rand"
+	"reflect"
 
 	"github.com/pkg/errors"
 	"gonum.org/v1/gonum/mat"
@@ -126,6 +127,25 @@ func (nn *NeuralNetwork) Predict(input *mat.Dense) *mat.Dense {
 	return output
 }
 
+// Equal returns wether both networks have the same structure, weights and biases.
+func (nn *NeuralNetwork) Equal(other *NeuralNetwork) bool {
+	if nn == other {
+		return true
+	}
+	if nn == nil || other == nil || !reflect.DeepEqual(nn.Structure, other.Structure) || nn.ActivationFuncName != other.ActivationFuncName {
+		return false
+	}
+	if len(nn.WHiddenByLayer) != len(other.WHiddenByLayer) || len(nn.BHiddenByLayer) != len(other.BHiddenByLayer) {
+		return false
+	}
+	for i := range nn.WHiddenByLayer {
+		if !mat.Equal(nn.WHiddenByLayer[i].M, other.WHiddenByLayer[i].M) || !mat.Equal(nn.BHiddenByLayer[i].M, other.BHiddenByLayer[i].M) {
+			return false
+		}
+	}
+	return mat.Equal(nn.WOut.M, other.WOut.M) && mat.Equal(nn.BOut.M, other.BOut.M)
+}
+
 func (nn *NeuralNetwork) Crossover(other *NeuralNetwork) *NeuralNetwork {
 	return &NeuralNetwork{
 		Structure:          nn.Structure,
@@ -1,10 +1,13 @@
 package player
 
 import (
+	"context"
+	"math"
 	"reflect"
 
 	"github.com/al-pi314/gogo"
 	"github.com/al-pi314/gogo/nn"
+	"github.com/al-pi314/gogo/rules"
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -14,6 +17,12 @@ type Agent struct {
 	Logic             *nn.NeuralNetwork
 	SuggestedOnMove   int
 	SuggestedMoves    *MoveSuggestionLinked
+	// ResignMargin is the score deficit at which the agent resigns, zero disables resigning.
+	ResignMargin float64
+	// IllegalSuggestions counts moves on which the network preferred an illegal position.
+	IllegalSuggestions int `json:"-"`
+
+	suggestedOnHash uint64
 }
 
 type MoveSuggestionLinked = gogo.LinkedList[MoveSuggestion]
@@ -78,8 +87,8 @@ func encodeState(state *GameState) *mat.Dense {
 		raw = append(raw, 0)
 	}
 
-	isMyPiece := state.MovesCount%2 == 1 // on even moves my pieces are black = false
-	isNotMyPiece := !isMyPiece           // oponents pieces are opposite color
+	isMyPiece := state.WhiteToMove // my pieces are white when white is to move
+	isNotMyPiece := !isMyPiece     // oponents pieces are opposite color
 
 	// encode game state
 	for i := range state.Board {
@@ -104,29 +113,55 @@ func encodeState(state *GameState) *mat.Dense {
 	return mat.NewDense(1, len(raw), raw)
 }
 
-func interperate(output *mat.Dense, dymension int) (bool, *MoveSuggestionLinked) {
+// interperate returns wether to skip, legal move suggestions ordered by effectivness and wether the most
+// effective position was illegal.
+func interperate(output *mat.Dense, state *GameState) (bool, *MoveSuggestionLinked, bool) {
 	if output == nil {
-		return false, nil
+		return false, nil, false
 	}
 
-	suggestions := MoveSuggestionLinked{
-		Element: MoveSuggestion{
-			X:            -1,
-			Y:            -1,
-			Effectivness: -1,
-		},
-	}
+	dymension := len(state.Board)
+	var suggestions *MoveSuggestionLinked
+	bestLegal, bestIllegal := -1.0, -1.0
 	for y := 0; y < dymension; y++ {
 		for x := 0; x < dymension; x++ {
-			suggestions = suggestions.Add(MoveSuggestion{
+			effectivness := output.At(0, y*dymension+x)
+			// mask illegal outputs
+			if !state.IsLegal(x, y) {
+				bestIllegal = math.Max(bestIllegal, effectivness)
+				continue
+			}
+			bestLegal = math.Max(bestLegal, effectivness)
+
+			suggestion := MoveSuggestion{
 				X:            x,
 				Y:            y,
-				Effectivness: output.At(0, y*dymension+x),
-			})
+				Effectivness: effectivness,
+			}
+			if suggestions == nil {
+				suggestions = &MoveSuggestionLinked{Element: suggestion}
+				continue
+			}
+			added := suggestions.Add(suggestion)
+			suggestions = &added
 		}
 	}
 
-	return output.At(0, dymension*dymension) >= 0.9, &suggestions
+	return output.At(0, dymension*dymension) >= 0.9, suggestions, bestIllegal > bestLegal
+}
+
+// Policy returns network effectivness of every board position indexed by [y][x] and the skip output.
+func (p *Agent) Policy(state *GameState) ([][]float64, float64) {
+	output := p.Logic.Predict(encodeState(state))
+	dymension := len(state.Board)
+	policy := make([][]float64, dymension)
+	for y := range policy {
+		policy[y] = make([]float64, dymension)
+		for x := range policy[y] {
+			policy[y][x] = output.At(0, y*dymension+x)
+		}
+	}
+	return policy, output.At(0, dymension*dymension)
 }
 
 func (p *Agent) Crossover(other *Agent) *Agent {
@@ -134,39 +169,66 @@ func (p *Agent) Crossover(other *Agent) *Agent {
 	a := NewAgent(Agent{
 		StabilizationRate: p.StabilizationRate,
 		MutationRate:      (1 - p.StabilizationRate) * p.MutationRate,
+		ResignMargin:      p.ResignMargin,
 		Logic:             newLogic.Mutate(p.MutationRate),
 	})
 	return &a
 }
 
-func (p *Agent) Place(state *GameState) (bool, *int, *int) {
+// hopeless returns wether the game is lost by more than ResignMargin points. Score is only trusted once half
+// of the board was played, before that a single piece seems to own the whole board.
+func (p *Agent) hopeless(state *GameState) bool {
+	if p.ResignMargin <= 0 || state.MovesCount < len(state.Board)*len(state.Board)/2 {
+		return false
+	}
+	score := state.Score()
+	if !state.WhiteToMove {
+		score = -score
+	}
+	return score < -p.ResignMargin
+}
+
+// Place returns the most effective move suggested by the network. Suggestions are cached for the position so
+// the next best move is returned when the previous one was not accepted.
+func (p *Agent) Place(ctx context.Context, state *GameState) (Move, error) {
+	if err := ctx.Err(); err != nil {
+		return Move{}, err
+	}
 	if state == nil {
-		return false, nil, nil
+		return Move{}, ErrNoMove
 	}
 
+	if p.hopeless(state) {
+		return rules.Resign(), nil
+	}
 	if state.MovesCount > len(state.Board)*len(state.Board) {
-		return true, nil, nil
+		return rules.Pass(), nil
 	}
 
-	if p.SuggestedOnMove != state.MovesCount {
+	// moves can be taken back so the position is compared as well
+	if p.SuggestedOnMove != state.MovesCount || p.suggestedOnHash != state.Hash {
 		// refresh cached moves suggestions
-		var skip bool
+		var skip, preferredIllegal bool
 		result := p.Logic.Predict(encodeState(state))
-		skip, p.SuggestedMoves = interperate(result, len(state.Board))
+		skip, p.SuggestedMoves, preferredIllegal = interperate(result, state)
+		if preferredIllegal {
+			p.IllegalSuggestions++
+		}
 		if skip || p.SuggestedMoves == nil {
-			return true, nil, nil
+			return rules.Pass(), nil
 		}
 		p.SuggestedOnMove = state.MovesCount
+		p.suggestedOnHash = state.Hash
 	}
 
 	// no more suggeste moves means no possible moves
 	if p.SuggestedMoves == nil {
-		return true, nil, nil
+		return rules.Pass(), nil
 	}
 
 	// pick best move from cached suggestions
 	bestMove := *p.SuggestedMoves
 	p.SuggestedMoves = bestMove.Next
 
-	return false, &bestMove.Element.X, &bestMove.Element.Y
+	return rules.Play(bestMove.Element.X, bestMove.Element.Y), nil
 }
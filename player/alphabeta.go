@@ -0,0 +1,322 @@
+package player
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/al-pi314/gogo/nn"
+	"github.com/al-pi314/gogo/rules"
+)
+
+// Evaluator rates positions for search players from the point of view of the player to move, better
+// positions have higher values.
+type Evaluator interface {
+	Evaluate(*GameState) float64
+}
+
+// TerritoryEvaluator rates positions by the game score counted with the game ruleset.
+type TerritoryEvaluator struct{}
+
+func (TerritoryEvaluator) Evaluate(state *GameState) float64 {
+	return perspective(state, state.Score())
+}
+
+// InfluenceEvaluator rates positions by area counting where empty positions belong to the player with the
+// closest piece. Unlike the game score it does not give the whole empty board to the first placed piece.
+type InfluenceEvaluator struct{}
+
+func (InfluenceEvaluator) Evaluate(state *GameState) float64 {
+	dymension := len(state.Board)
+	// distance to the closest piece of each player, found by breadth first search from all pieces
+	distances := [2][]int{make([]int, dymension*dymension), make([]int, dymension*dymension)}
+	for color := range distances {
+		queue := []int{}
+		for p := range distances[color] {
+			piece := state.PieceAt(p%dymension, p/dymension)
+			if piece != nil && *piece == (color == 1) {
+				queue = append(queue, p)
+				continue
+			}
+			distances[color][p] = -1
+		}
+		for len(queue) > 0 {
+			p := queue[0]
+			queue = queue[1:]
+			x, y := p%dymension, p/dymension
+			for _, n := range [4][2]int{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+				if n[0] < 0 || n[0] >= dymension || n[1] < 0 || n[1] >= dymension {
+					continue
+				}
+				q := n[1]*dymension + n[0]
+				if distances[color][q] < 0 && state.PieceAt(n[0], n[1]) == nil {
+					distances[color][q] = distances[color][p] + 1
+					queue = append(queue, q)
+				}
+			}
+		}
+	}
+
+	score := state.Settings.Komi
+	for p := range distances[0] {
+		black, white := distances[0][p], distances[1][p]
+		switch {
+		case white >= 0 && (black < 0 || white < black):
+			score++
+		case black >= 0 && (white < 0 || black < white):
+			score--
+		}
+	}
+	return perspective(state, score)
+}
+
+// NetworkEvaluator rates positions by agent network outputs for the player to move. Networks with an output
+// after the skip output use it as the value output (chance of the player to move winning), other networks use
+// effectivness of the best legal move, or the skip output when no move is legal. Values are scaled to the board
+// area so they can be compared with the game score of finished games.
+type NetworkEvaluator struct {
+	Network *nn.NeuralNetwork
+}
+
+func (e NetworkEvaluator) Evaluate(state *GameState) float64 {
+	output := e.Network.Predict(encodeState(state))
+	dymension := len(state.Board)
+	area := float64(dymension * dymension)
+	if _, columns := output.Dims(); columns > dymension*dymension+1 {
+		return (2*output.At(0, dymension*dymension+1) - 1) * area
+	}
+
+	value := output.At(0, dymension*dymension)
+	legal := state.LegalMoves()
+	for i, c := range legal {
+		effectivness := output.At(0, c.Y*dymension+c.X)
+		if i == 0 || effectivness > value {
+			value = effectivness
+		}
+	}
+	return (2*value - 1) * area
+}
+
+// perspective turns score of white into score of the player to move.
+func perspective(state *GameState, score float64) float64 {
+	if !state.WhiteToMove {
+		return -score
+	}
+	return score
+}
+
+// AlphaBeta chooses moves with depth limited alpha-beta search. Search is deepened iteratively until the depth
+// or the time budget is reached, searched positions are kept in a transposition table between moves.
+type AlphaBeta struct {
+	// Depth limits search depth, zero deepens until the time budget runs out.
+	Depth int
+	// Budget limits thinking time per move, zero only limits the depth.
+	Budget    time.Duration
+	Evaluator Evaluator
+	// TableSize limits number of positions in the transposition table.
+	TableSize int
+
+	// lock keeps searches canceled by the caller from changing the table while the next search runs
+	lock  *sync.Mutex
+	table map[uint64]tableEntry
+	// tableFor holds game rules, board size and evaluator the table was filled with
+	tableFor tableKey
+}
+
+type tableKey struct {
+	rules     rules.Settings
+	dymension int
+	evaluator Evaluator
+}
+
+type bound int
+
+const (
+	exactBound bound = iota
+	lowerBound
+	upperBound
+)
+
+type tableEntry struct {
+	depth int
+	value float64
+	bound bound
+	best  Move
+}
+
+const (
+	defaultDepth     = 3
+	defaultTableSize = 1 << 20
+)
+
+func NewAlphaBeta(p AlphaBeta) *AlphaBeta {
+	if p.Depth <= 0 && p.Budget <= 0 {
+		p.Depth = defaultDepth
+	}
+	if p.Evaluator == nil {
+		p.Evaluator = InfluenceEvaluator{}
+	}
+	if p.TableSize <= 0 {
+		p.TableSize = defaultTableSize
+	}
+	p.lock = &sync.Mutex{}
+	p.table = map[uint64]tableEntry{}
+	return &p
+}
+
+func (p *AlphaBeta) IsHuman() bool {
+	return false
+}
+
+// Place returns the best move found by the deepest completed search. When the context has a deadline at most
+// a tenth of the remaining time is used.
+func (p *AlphaBeta) Place(ctx context.Context, state *GameState) (Move, error) {
+	if err := ctx.Err(); err != nil {
+		return Move{}, err
+	}
+	if state == nil {
+		return Move{}, ErrNoMove
+	}
+
+	var stop time.Time
+	if p.Budget > 0 {
+		stop = time.Now().Add(p.Budget)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		share := time.Now().Add(time.Until(deadline) / 10)
+		if stop.IsZero() || share.Before(stop) {
+			stop = share
+		}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	// values of positions depend on the rules and the evaluation
+	key := tableKey{rules: state.Settings, dymension: len(state.Board), evaluator: p.Evaluator}
+	if len(p.table) > p.TableSize || !reflect.DeepEqual(key, p.tableFor) {
+		p.table = map[uint64]tableEntry{}
+		p.tableFor = key
+	}
+
+	best := rules.Pass()
+	// deeper search than the number of empty positions does not find anything new
+	maxDepth := len(state.Board)*len(state.Board) - state.WhiteStones - state.BlackStones + 2
+	for depth := 1; (p.Depth <= 0 || depth <= p.Depth) && depth <= maxDepth; depth++ {
+		if _, ok := p.search(ctx, stop, state, depth, math.Inf(-1), math.Inf(1)); !ok {
+			break
+		}
+		best = p.table[state.Key()].best
+	}
+	return best, nil
+}
+
+// timeout returns wether the search has to stop.
+func timeout(ctx context.Context, stop time.Time) bool {
+	return ctx.Err() != nil || (!stop.IsZero() && time.Now().After(stop))
+}
+
+// search returns value of the position for the player to move (negamax). Returns false when the search was
+// stopped before it finished.
+func (p *AlphaBeta) search(ctx context.Context, stop time.Time, state *GameState, depth int, alpha, beta float64) (float64, bool) {
+	if timeout(ctx, stop) {
+		return 0, false
+	}
+	if state.Finished {
+		return TerritoryEvaluator{}.Evaluate(state), true
+	}
+	if depth == 0 {
+		return p.Evaluator.Evaluate(state), true
+	}
+
+	key := state.Key()
+	entry, cached := p.table[key]
+	if cached && entry.depth >= depth {
+		switch {
+		case entry.bound == exactBound:
+			return entry.value, true
+		case entry.bound == lowerBound && entry.value >= beta:
+			return entry.value, true
+		case entry.bound == upperBound && entry.value <= alpha:
+			return entry.value, true
+		}
+	}
+
+	startAlpha := alpha
+	best := math.Inf(-1)
+	var bestMove Move
+	for _, m := range p.orderMoves(state, entry.best, cached) {
+		// moves from the table can be illegal in positions with different history
+		child := state.Clone()
+		if !child.Apply(m) {
+			continue
+		}
+
+		var value float64
+		var ok bool
+		// player keeps the move when placing free handicap
+		if child.WhiteToMove == state.WhiteToMove {
+			value, ok = p.search(ctx, stop, child, depth-1, alpha, beta)
+		} else {
+			value, ok = p.search(ctx, stop, child, depth-1, -beta, -alpha)
+			value = -value
+		}
+		if !ok {
+			return 0, false
+		}
+
+		if value > best {
+			best, bestMove = value, m
+		}
+		alpha = math.Max(alpha, value)
+		if alpha >= beta {
+			break
+		}
+	}
+
+	entry = tableEntry{depth: depth, value: best, best: bestMove}
+	switch {
+	case best <= startAlpha:
+		entry.bound = upperBound
+	case best >= beta:
+		entry.bound = lowerBound
+	}
+	p.table[key] = entry
+	return best, true
+}
+
+// orderMoves returns legal moves that do not fill own eyes. Best move from the previous search is tried first,
+// then moves capturing the most pieces, skip is tried last.
+func (p *AlphaBeta) orderMoves(state *GameState, previous Move, hasPrevious bool) []Move {
+	type candidate struct {
+		move     Move
+		captures int
+	}
+	candidates := []candidate{}
+	for _, c := range state.LegalMoves() {
+		if state.IsEye(c.X, c.Y, state.WhiteToMove) {
+			continue
+		}
+		captured, _ := state.Captures(c.X, c.Y)
+		candidates = append(candidates, candidate{rules.Play(c.X, c.Y), captured})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].captures > candidates[j].captures
+	})
+
+	moves := []Move{}
+	if hasPrevious {
+		moves = append(moves, previous)
+	}
+	for _, c := range candidates {
+		if !hasPrevious || c.move != previous {
+			moves = append(moves, c.move)
+		}
+	}
+	if !hasPrevious || previous.Kind != rules.PassMove {
+		moves = append(moves, rules.Pass())
+	}
+	return moves
+}
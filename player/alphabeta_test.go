@@ -0,0 +1,106 @@
+package player
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/al-pi314/gogo"
+	"github.com/al-pi314/gogo/nn"
+	"github.com/al-pi314/gogo/rules"
+)
+
+// constantNetwork returns network without hidden layers whose outputs are sigmoid of the given biases.
+func constantNetwork(dymension int, biases []float64) *nn.NeuralNetwork {
+	network := nn.NewNeuralNetwork(nn.NeuralNetwork{
+		Structure: nn.Structure{
+			InputNeurons:  3*dymension*dymension + gogo.GameStateSize(),
+			OutputNeurons: len(biases),
+		},
+		ActivationFuncName: "SIGMOID",
+	})
+	network.WOut.M.Zero()
+	for i, b := range biases {
+		network.BOut.M.Set(0, i, b)
+	}
+	return network
+}
+
+func sigmoid(v float64) float64 {
+	return 1 / (1 + math.Exp(-v))
+}
+
+func TestNetworkEvaluator(t *testing.T) {
+	const dymension = 3
+	area := float64(dymension * dymension)
+	// biases of board outputs, skip output and optional value output
+	policy := func(best, bias, skip float64, value ...float64) []float64 {
+		biases := make([]float64, dymension*dymension)
+		biases[0] = best
+		for i := 1; i < len(biases); i++ {
+			biases[i] = bias
+		}
+		return append(append(biases, skip), value...)
+	}
+	occupied := rules.NewGameState(dymension, rules.Settings{})
+	occupied.Apply(rules.Play(0, 0))
+	full := rules.NewGameState(2, rules.Settings{})
+	full.Apply(rules.Pass())
+	full.Apply(rules.Play(1, 0))
+	full.Apply(rules.Pass())
+	full.Apply(rules.Play(0, 1))
+
+	tests := []struct {
+		name   string
+		state  *GameState
+		biases []float64
+		want   float64
+	}{
+		{
+			name:   "value output",
+			state:  rules.NewGameState(dymension, rules.Settings{}),
+			biases: policy(3, 0, 0, 2),
+			want:   (2*sigmoid(2) - 1) * area,
+		},
+		{
+			name:   "best move output",
+			state:  rules.NewGameState(dymension, rules.Settings{}),
+			biases: policy(3, 0, 0),
+			want:   (2*sigmoid(3) - 1) * area,
+		},
+		{
+			name:   "illegal moves are ignored",
+			state:  occupied,
+			biases: policy(3, -1, 0),
+			want:   (2*sigmoid(-1) - 1) * area,
+		},
+		{
+			name:   "skip output without legal moves",
+			state:  full,
+			biases: []float64{0, 0, 0, 0, -2},
+			want:   (2*sigmoid(-2) - 1) * 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := NetworkEvaluator{Network: constantNetwork(len(test.state.Board), test.biases)}
+			if got := e.Evaluate(test.state); math.Abs(got-test.want) > 1e-9 {
+				t.Fatalf("value %f, expected %f", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAlphaBetaNetworkEvaluator(t *testing.T) {
+	// network values every position the same, search still returns a legal move
+	state := rules.NewGameState(3, rules.Settings{})
+	p := NewAlphaBeta(AlphaBeta{Depth: 2, Evaluator: NetworkEvaluator{Network: constantNetwork(3, make([]float64, 11))}})
+	m, err := p.Place(context.Background(), state)
+	if err != nil {
+		t.Fatalf("failed to place: %v", err)
+	}
+	if m.Kind == rules.PlayMove && !state.IsLegal(m.X, m.Y) {
+		t.Fatalf("move %s is not legal", m)
+	}
+}
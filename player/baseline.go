@@ -0,0 +1,169 @@
+package player
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/al-pi314/gogo/rules"
+	"github.com/pkg/errors"
+)
+
+// Baseline players play fixed strategies so agents can be measured against opponents that do not change
+// between rounds. Random choices are seeded so games against baselines can be repeated.
+
+// baseline holds random source shared by baseline players.
+type baseline struct {
+	Seed int64
+
+	rng *rand.Rand
+}
+
+func (b *baseline) IsHuman() bool {
+	return false
+}
+
+// pick returns random move among the best rated candidates, rating nil skips the candidate. Returns pass when
+// there are no candidates.
+func (b *baseline) pick(ctx context.Context, state *GameState, rate func(rules.Cordinate) *int) (Move, error) {
+	if err := ctx.Err(); err != nil {
+		return Move{}, err
+	}
+	if state == nil {
+		return Move{}, ErrNoMove
+	}
+	if b.rng == nil {
+		b.rng = rand.New(rand.NewSource(b.Seed))
+	}
+
+	best := []rules.Cordinate{}
+	var bestRating int
+	for _, c := range state.LegalMoves() {
+		rating := rate(c)
+		if rating == nil {
+			continue
+		}
+		if len(best) == 0 || *rating > bestRating {
+			best = best[:0]
+			bestRating = *rating
+		}
+		if *rating == bestRating {
+			best = append(best, c)
+		}
+	}
+	if len(best) == 0 {
+		return rules.Pass(), nil
+	}
+	c := best[b.rng.Intn(len(best))]
+	return rules.Play(c.X, c.Y), nil
+}
+
+func rating(r int) *int {
+	return &r
+}
+
+// Random plays uniformly random legal moves (including moves filling its own eyes) and passes when only moves
+// filling its own eyes are left, so games between random players end under every ko rule.
+type Random struct {
+	baseline
+}
+
+func NewRandom(seed int64) *Random {
+	return &Random{baseline{Seed: seed}}
+}
+
+func (p *Random) Place(ctx context.Context, state *GameState) (Move, error) {
+	onlyEyes := true
+	if state != nil {
+		for _, c := range state.LegalMoves() {
+			if !state.IsEye(c.X, c.Y, state.WhiteToMove) {
+				onlyEyes = false
+				break
+			}
+		}
+	}
+	return p.pick(ctx, state, func(rules.Cordinate) *int {
+		if onlyEyes {
+			return nil
+		}
+		return rating(0)
+	})
+}
+
+// EyeAvoider plays random legal moves that do not fill its own eyes.
+type EyeAvoider struct {
+	baseline
+}
+
+func NewEyeAvoider(seed int64) *EyeAvoider {
+	return &EyeAvoider{baseline{Seed: seed}}
+}
+
+func (p *EyeAvoider) Place(ctx context.Context, state *GameState) (Move, error) {
+	return p.pick(ctx, state, func(c rules.Cordinate) *int {
+		if state.IsEye(c.X, c.Y, state.WhiteToMove) {
+			return nil
+		}
+		return rating(0)
+	})
+}
+
+// Capturer plays moves capturing the most opponent pieces and does not fill its own eyes.
+type Capturer struct {
+	baseline
+}
+
+func NewCapturer(seed int64) *Capturer {
+	return &Capturer{baseline{Seed: seed}}
+}
+
+func (p *Capturer) Place(ctx context.Context, state *GameState) (Move, error) {
+	return p.pick(ctx, state, func(c rules.Cordinate) *int {
+		if state.IsEye(c.X, c.Y, state.WhiteToMove) {
+			return nil
+		}
+		captured, _ := state.Captures(c.X, c.Y)
+		return rating(captured)
+	})
+}
+
+// AtariAvoider plays moves leaving the least of its pieces in atari, it saves groups in atari and avoids
+// placing pieces that can be captured right away. It does not fill its own eyes.
+type AtariAvoider struct {
+	baseline
+}
+
+func NewAtariAvoider(seed int64) *AtariAvoider {
+	return &AtariAvoider{baseline{Seed: seed}}
+}
+
+func (p *AtariAvoider) Place(ctx context.Context, state *GameState) (Move, error) {
+	return p.pick(ctx, state, func(c rules.Cordinate) *int {
+		white := state.WhiteToMove
+		if state.IsEye(c.X, c.Y, white) {
+			return nil
+		}
+		next := state.Clone()
+		next.Place(c.X, c.Y)
+		return rating(-next.PiecesInAtari(white))
+	})
+}
+
+// NewBaseline returns baseline player by name ("random", "eyes", "capture" or "atari").
+func NewBaseline(name string, seed int64) (Player, error) {
+	switch name {
+	case "random":
+		return NewRandom(seed), nil
+	case "eyes":
+		return NewEyeAvoider(seed), nil
+	case "capture":
+		return NewCapturer(seed), nil
+	case "atari":
+		return NewAtariAvoider(seed), nil
+	}
+	return nil, errors.Errorf("unknown baseline player %q", name)
+}
+
+// BaselineNames returns names of all baseline players.
+func BaselineNames() []string {
+	return []string{"random", "eyes", "capture", "atari"}
+}
@@ -0,0 +1,266 @@
+package player
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/al-pi314/gogo/gtp"
+	"github.com/al-pi314/gogo/rules"
+	"github.com/pkg/errors"
+)
+
+// GTP plays moves generated by an external engine process speaking Go Text Protocol.
+type GTP struct {
+	// Command holds engine executable and its arguments.
+	Command []string
+
+	cmd       *exec.Cmd
+	in        io.WriteCloser
+	out       *bufio.Reader
+	dymension int
+	known     int
+	// history is hash of the game setup and the moves known by the engine
+	history uint64
+	// generated is the last generated move, generatedAt and generatedKey identify position it was generated on
+	generated    Move
+	generatedAt  int
+	generatedKey uint64
+	// pending receives response of the command the player stopped waiting for
+	pending chan gtpResponse
+}
+
+type gtpResponse struct {
+	text string
+	err  error
+}
+
+// NewGTP starts the engine process.
+func NewGTP(p GTP) (*GTP, error) {
+	if len(p.Command) == 0 {
+		return nil, errors.New("gtp engine command is empty")
+	}
+
+	p.cmd = exec.Command(p.Command[0], p.Command[1:]...)
+	p.cmd.Stderr = os.Stderr
+	var err error
+	if p.in, err = p.cmd.StdinPipe(); err != nil {
+		return nil, errors.Wrap(err, "failed to open gtp engine input")
+	}
+	out, err := p.cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open gtp engine output")
+	}
+	p.out = bufio.NewReader(out)
+	if err := p.cmd.Start(); err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to start gtp engine %q", strings.Join(p.Command, " ")))
+	}
+
+	p.dymension = -1
+	p.generatedAt = -1
+	return &p, nil
+}
+
+func (p *GTP) IsHuman() bool {
+	return false
+}
+
+// Close quits the engine and waits for the process to exit.
+func (p *GTP) Close() error {
+	if _, err := p.send("quit"); err != nil {
+		p.cmd.Process.Kill()
+	}
+	p.in.Close()
+	return p.cmd.Wait()
+}
+
+// send sends a command to the engine and returns its response.
+func (p *GTP) send(command string) (string, error) {
+	p.drain()
+	return p.exchange(command)
+}
+
+// sendContext sends a command to the engine and waits for its response until the context is done. Response
+// of the abandoned command is read before the next command is sent.
+func (p *GTP) sendContext(ctx context.Context, command string) (string, error) {
+	p.drain()
+	p.pending = make(chan gtpResponse, 1)
+	go func(result chan<- gtpResponse) {
+		text, err := p.exchange(command)
+		result <- gtpResponse{text, err}
+	}(p.pending)
+
+	select {
+	case r := <-p.pending:
+		p.pending = nil
+		return r.text, r.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// drain waits for response of the abandoned command.
+func (p *GTP) drain() {
+	if p.pending != nil {
+		<-p.pending
+		p.pending = nil
+	}
+}
+
+// exchange writes the command and reads the engine response.
+func (p *GTP) exchange(command string) (string, error) {
+	if _, err := fmt.Fprintf(p.in, "%s\n", command); err != nil {
+		return "", errors.Wrap(err, "failed to send gtp command")
+	}
+
+	lines := []string{}
+	for {
+		line, err := p.out.ReadString('\n')
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read gtp response")
+		}
+		line = strings.TrimRight(line, "\r\n")
+		// response ends with an empty line
+		if line == "" && len(lines) > 0 {
+			break
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	response := strings.Join(lines, "\n")
+	status, response := response[0], strings.TrimSpace(strings.TrimLeft(response[1:], "0123456789"))
+	if status != '=' {
+		return "", errors.Errorf("gtp command %q failed: %s", command, response)
+	}
+	return response, nil
+}
+
+// extendHistory returns history hash with the move added.
+func extendHistory(history uint64, white bool, move [2]*int) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d;%t", history, white)
+	if move[0] != nil && move[1] != nil {
+		fmt.Fprintf(h, ";%d;%d", *move[0], *move[1])
+	}
+	return h.Sum64()
+}
+
+// historyHash returns hash of the game setup and the first n moves of the game.
+func historyHash(state *GameState, n int) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d;%g;%v", len(state.Board), state.Settings.Komi, state.Setup)
+	history := h.Sum64()
+	for i := 0; i < n; i++ {
+		history = extendHistory(history, state.MoveColors[i], state.Moves[i])
+	}
+	return history
+}
+
+// reset starts a new game on the engine matching the game state settings.
+func (p *GTP) reset(state *GameState) error {
+	p.dymension = len(state.Board)
+	p.known = 0
+	p.history = historyHash(state, 0)
+	commands := []string{
+		fmt.Sprintf("boardsize %d", p.dymension),
+		"clear_board",
+		fmt.Sprintf("komi %g", state.Settings.Komi),
+	}
+	if len(state.Setup) > 0 {
+		vertices := []string{}
+		for _, c := range state.Setup {
+			x, y := c.X, c.Y
+			vertices = append(vertices, gtp.Vertex(&x, &y, p.dymension))
+		}
+		commands = append(commands, "set_free_handicap "+strings.Join(vertices, " "))
+	}
+
+	for _, command := range commands {
+		if _, err := p.send(command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *GTP) sync(state *GameState) error {
+	// game was reset, moves were taken back or the game history changed
+	if p.dymension != len(state.Board) || p.known > len(state.Moves) || p.history != historyHash(state, p.known) {
+		if err := p.reset(state); err != nil {
+			return err
+		}
+	}
+
+	for ; p.known < len(state.Moves); p.known++ {
+		color := "b"
+		if state.MoveColors[p.known] {
+			color = "w"
+		}
+		move := state.Moves[p.known]
+		if _, err := p.send(fmt.Sprintf("play %s %s", color, gtp.Vertex(move[0], move[1], p.dymension))); err != nil {
+			return err
+		}
+		p.history = extendHistory(p.history, state.MoveColors[p.known], move)
+	}
+	return nil
+}
+
+// Place asks the engine to generate the move. When the context is done before the engine responds, its move
+// is discarded and the game is synced again on the next move.
+func (p *GTP) Place(ctx context.Context, state *GameState) (Move, error) {
+	if state == nil {
+		return Move{}, ErrNoMove
+	}
+
+	// engine move was not accepted by the game, skip instead of suggesting it again. Moves discarded by the
+	// game (e.g. taken back while the engine was thinking) are legal and are generated again.
+	if p.generatedAt == len(state.Moves) && p.generatedKey == state.Key() {
+		p.generatedAt = -1
+		if p.generated.Kind == rules.PlayMove && !state.IsLegal(p.generated.X, p.generated.Y) {
+			return rules.Pass(), nil
+		}
+	}
+
+	if err := p.sync(state); err != nil {
+		p.dymension = -1
+		return Move{}, errors.Wrap(err, "failed to sync game with gtp engine")
+	}
+
+	color := "b"
+	if state.WhiteToMove {
+		color = "w"
+	}
+	vertex, err := p.sendContext(ctx, "genmove "+color)
+	if err != nil {
+		p.dymension = -1
+		return Move{}, errors.Wrap(err, "gtp engine failed to generate move")
+	}
+	if strings.EqualFold(vertex, "resign") {
+		// engine did not play a move, resync if the game continues
+		p.dymension = -1
+		return rules.Resign(), nil
+	}
+
+	x, y, err := gtp.ParseVertex(vertex, p.dymension)
+	if err != nil {
+		p.dymension = -1
+		return Move{}, errors.Wrap(err, "gtp engine generated invalid move")
+	}
+	// engine board holds the generated move, it is replaced on the next sync when the game did not accept it
+	p.known++
+	p.history = extendHistory(p.history, state.WhiteToMove, [2]*int{x, y})
+	p.generated = rules.Pass()
+	if x != nil && y != nil {
+		p.generated = rules.Play(*x, *y)
+	}
+	p.generatedAt = len(state.Moves)
+	p.generatedKey = state.Key()
+	return p.generated, nil
+}
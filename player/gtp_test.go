@@ -0,0 +1,173 @@
+package player
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/al-pi314/gogo/gtp"
+	"github.com/al-pi314/gogo/rules"
+)
+
+// engineEnv selects the engine played by the test binary when it is started as a gtp engine process.
+const engineEnv = "GOGO_TEST_GTP_ENGINE"
+
+// resigner resigns every game.
+type resigner struct{}
+
+func (resigner) Place(context.Context, *rules.GameState) (rules.Move, error) {
+	return rules.Resign(), nil
+}
+
+// slowPlayer plays as the fake player after a delay.
+type slowPlayer struct {
+	gtp.FakePlayer
+}
+
+func (p *slowPlayer) Place(ctx context.Context, state *rules.GameState) (rules.Move, error) {
+	time.Sleep(300 * time.Millisecond)
+	return p.FakePlayer.Place(ctx, state)
+}
+
+func TestMain(m *testing.M) {
+	var p gtp.Player
+	switch os.Getenv(engineEnv) {
+	case "":
+		os.Exit(m.Run())
+	case "resign":
+		p = resigner{}
+	case "slow":
+		p = &slowPlayer{}
+	default:
+		p = &gtp.FakePlayer{}
+	}
+	engine := gtp.NewEngine(gtp.Engine{Name: "test", Player: p, Dymension: 9})
+	if err := engine.Run(os.Stdin, os.Stdout); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func startTestEngine(t *testing.T, mode string) *GTP {
+	t.Helper()
+	t.Setenv(engineEnv, mode)
+	p, err := NewGTP(GTP{Command: []string{os.Args[0]}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func place(t *testing.T, p *GTP, state *GameState) Move {
+	t.Helper()
+	m, err := p.Place(context.Background(), state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func play(t *testing.T, state *GameState, moves ...Move) {
+	t.Helper()
+	for _, m := range moves {
+		if !state.Apply(m) {
+			t.Fatalf("move %s is not legal", m)
+		}
+	}
+}
+
+func TestGTPGenmove(t *testing.T) {
+	p := startTestEngine(t, "fake")
+	state := rules.NewGameState(5, rules.Settings{Komi: 0.5})
+
+	if m := place(t, p, state); m != rules.Play(0, 0) {
+		t.Fatalf("first move %s, expected (0, 0)", m)
+	}
+	play(t, state, rules.Play(0, 0), rules.Play(1, 0))
+	if m := place(t, p, state); m != rules.Play(2, 0) {
+		t.Fatalf("second move %s, expected (2, 0)", m)
+	}
+}
+
+func TestGTPPass(t *testing.T) {
+	p := startTestEngine(t, "fake")
+	// both empty positions are suicide for black
+	state := rules.NewGameState(2, rules.Settings{})
+	play(t, state, rules.Pass(), rules.Play(1, 0), rules.Pass(), rules.Play(0, 1))
+
+	if m := place(t, p, state); m != rules.Pass() {
+		t.Fatalf("move %s, expected pass", m)
+	}
+}
+
+func TestGTPResign(t *testing.T) {
+	p := startTestEngine(t, "resign")
+	state := rules.NewGameState(5, rules.Settings{})
+
+	if m := place(t, p, state); m != rules.Resign() {
+		t.Fatalf("move %s, expected resign", m)
+	}
+	// engine is synced again when the game continues
+	play(t, state, rules.Play(2, 2))
+	if m := place(t, p, state); m != rules.Resign() {
+		t.Fatalf("move %s, expected resign", m)
+	}
+}
+
+func TestGTPSyncAfterUndo(t *testing.T) {
+	p := startTestEngine(t, "fake")
+	timeline := rules.NewTimeline(rules.NewGameState(5, rules.Settings{}))
+
+	for i := 0; i < 3; i++ {
+		timeline.Apply(place(t, p, timeline.State()))
+		timeline.Apply(rules.Play(4, 4-i))
+	}
+
+	// moves are taken back and a different game with more moves is played
+	timeline.Seek(0)
+	for _, m := range []Move{rules.Play(0, 1), rules.Play(1, 1), rules.Play(2, 1), rules.Play(3, 1), rules.Play(4, 1), rules.Play(0, 2), rules.Play(1, 2)} {
+		if !timeline.Apply(m) {
+			t.Fatalf("move %s is not legal", m)
+		}
+	}
+	if m := place(t, p, timeline.State()); m != rules.Play(0, 0) {
+		t.Fatalf("move %s, expected (0, 0) on the new game", m)
+	}
+
+	// moves are taken back to the first position
+	timeline.Seek(0)
+	if m := place(t, p, timeline.State()); m != rules.Play(0, 0) {
+		t.Fatalf("move %s, expected (0, 0) on the empty board", m)
+	}
+}
+
+func TestGTPDiscardedMove(t *testing.T) {
+	p := startTestEngine(t, "fake")
+	state := rules.NewGameState(5, rules.Settings{})
+
+	// game discarded the move (e.g. moves were taken back while the engine was thinking), it is generated again
+	place(t, p, state)
+	if m := place(t, p, state); m != rules.Play(0, 0) {
+		t.Fatalf("move %s, expected (0, 0) to be generated again", m)
+	}
+}
+
+func TestGTPCancel(t *testing.T) {
+	p := startTestEngine(t, "slow")
+	state := rules.NewGameState(5, rules.Settings{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := p.Place(ctx, state); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error %v, expected deadline exceeded", err)
+	}
+
+	// response of the abandoned move is drained before the engine is synced again
+	play(t, state, rules.Play(0, 0))
+	if m := place(t, p, state); m != rules.Play(1, 0) {
+		t.Fatalf("move %s, expected (1, 0)", m)
+	}
+}
@@ -1,13 +1,25 @@
 package player
 
 import (
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"context"
+
+	"github.com/al-pi314/gogo/rules"
 )
 
+// Input provides user actions to human players. It is implemented by the game window.
+type Input interface {
+	// Click returns cursor position of a mouse click made since the last update.
+	Click() (int, int, bool)
+	// Skip returns wether user requested to skip the move.
+	Skip() bool
+	// Resign returns wether user requested to resign the game.
+	Resign() bool
+}
+
 type Human struct {
 	XSnap int
 	YSnap int
+	Input Input
 }
 
 func NewHuman(p Human) *Human {
@@ -18,18 +30,22 @@ func (p *Human) IsHuman() bool {
 	return true
 }
 
-// Place implements player logic for placing their piece. Returns wether to place the piece or not, piece position and weather to skip move.
-func (p *Human) Place(state *GameState) (bool, *int, *int) {
-	if ebiten.IsFocused() {
-		switch {
-		case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft):
-			x, y := ebiten.CursorPosition()
-			x /= p.XSnap
-			y /= p.YSnap
-			return false, &x, &y
-		case inpututil.IsKeyJustPressed(ebiten.KeySpace):
-			return true, nil, nil
-		}
+// Place returns the move chosen by user since the last call. Returns ErrNoMove while the user is deciding.
+func (p *Human) Place(ctx context.Context, state *GameState) (Move, error) {
+	if err := ctx.Err(); err != nil {
+		return Move{}, err
+	}
+	if p.Input == nil {
+		return Move{}, ErrNoMove
+	}
+	if x, y, ok := p.Input.Click(); ok {
+		return rules.Play(x/p.XSnap, y/p.YSnap), nil
+	}
+	if p.Input.Skip() {
+		return rules.Pass(), nil
+	}
+	if p.Input.Resign() {
+		return rules.Resign(), nil
 	}
-	return false, nil, nil
+	return Move{}, ErrNoMove
 }
@@ -0,0 +1,248 @@
+package player
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/al-pi314/gogo/nn"
+	"github.com/al-pi314/gogo/rules"
+)
+
+// MCTS chooses moves with Monte Carlo Tree Search (UCT). Optional network is used as move prior (PUCT) and
+// can also replace random moves in rollouts.
+type MCTS struct {
+	// Playouts is number of simulations per move, zero simulates until the time budget runs out.
+	Playouts int
+	// Budget limits thinking time per move, zero only limits the number of playouts.
+	Budget time.Duration
+	// Exploration weights visiting less explored moves.
+	Exploration float64
+	// Network guides the search when set.
+	Network *nn.NeuralNetwork
+	// NetworkRollouts plays network moves in rollouts instead of random ones.
+	NetworkRollouts bool
+	Seed            int64
+
+	rng *rand.Rand
+}
+
+type mctsNode struct {
+	move     Move
+	white    bool // color of the player that made the move
+	parent   *mctsNode
+	children []*mctsNode
+	expanded bool
+	visits   int
+	wins     float64
+	prior    float64
+}
+
+const (
+	defaultPlayouts    = 1000
+	defaultExploration = 1.4
+)
+
+func NewMCTS(p MCTS) *MCTS {
+	if p.Playouts <= 0 && p.Budget <= 0 {
+		p.Playouts = defaultPlayouts
+	}
+	if p.Exploration <= 0 {
+		p.Exploration = defaultExploration
+	}
+	if p.Network != nil {
+		p.Network.SetActivationFunc()
+	}
+	p.rng = rand.New(rand.NewSource(p.Seed))
+	return &p
+}
+
+func (p *MCTS) IsHuman() bool {
+	return false
+}
+
+// Place searches the game tree until the playouts or the time budget are used up and returns the most visited
+// move. When the context has a deadline at most a tenth of the remaining time is used.
+func (p *MCTS) Place(ctx context.Context, state *GameState) (Move, error) {
+	if err := ctx.Err(); err != nil {
+		return Move{}, err
+	}
+	if state == nil {
+		return Move{}, ErrNoMove
+	}
+
+	var stop time.Time
+	if p.Budget > 0 {
+		stop = time.Now().Add(p.Budget)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		share := time.Now().Add(time.Until(deadline) / 10)
+		if stop.IsZero() || share.Before(stop) {
+			stop = share
+		}
+	}
+
+	root := &mctsNode{white: !state.WhiteToMove}
+	for i := 0; p.Playouts <= 0 || i < p.Playouts; i++ {
+		if ctx.Err() != nil || (!stop.IsZero() && time.Now().After(stop)) {
+			break
+		}
+		p.simulate(root, state)
+	}
+
+	var best *mctsNode
+	for _, c := range root.children {
+		if best == nil || c.visits > best.visits {
+			best = c
+		}
+	}
+	if best == nil {
+		return rules.Pass(), nil
+	}
+	return best.move, nil
+}
+
+// simulate selects a leaf of the tree, expands it and backs up result of a rollout from it.
+func (p *MCTS) simulate(root *mctsNode, rootState *GameState) {
+	state := rootState.Clone()
+	node := root
+	for node.expanded && len(node.children) > 0 && !state.Finished {
+		node = p.selectChild(node)
+		state.Apply(node.move)
+	}
+	if !state.Finished && !node.expanded {
+		p.expand(node, state)
+		if len(node.children) > 0 {
+			node = p.selectChild(node)
+			state.Apply(node.move)
+		}
+	}
+
+	winner := p.evaluate(state)
+	for ; node != nil; node = node.parent {
+		node.visits++
+		switch {
+		case winner == nil:
+			node.wins += 0.5
+		case *winner == node.white:
+			node.wins++
+		}
+	}
+}
+
+// expand adds all moves of the player to move as children, moves filling own eyes are left out.
+func (p *MCTS) expand(node *mctsNode, state *GameState) {
+	node.expanded = true
+	white := state.WhiteToMove
+	moves := []Move{rules.Pass()}
+	for _, c := range state.LegalMoves() {
+		if !state.IsEye(c.X, c.Y, white) {
+			moves = append(moves, rules.Play(c.X, c.Y))
+		}
+	}
+	// unvisited children are tried in random order
+	p.rng.Shuffle(len(moves), func(i, j int) { moves[i], moves[j] = moves[j], moves[i] })
+
+	priors := p.priors(state, moves)
+	for i, m := range moves {
+		node.children = append(node.children, &mctsNode{
+			move:   m,
+			white:  white,
+			parent: node,
+			prior:  priors[i],
+		})
+	}
+}
+
+// priors returns network effectivness of the moves normalised to sum to one, uniform without network.
+func (p *MCTS) priors(state *GameState, moves []Move) []float64 {
+	priors := make([]float64, len(moves))
+	if p.Network == nil {
+		for i := range priors {
+			priors[i] = 1 / float64(len(moves))
+		}
+		return priors
+	}
+
+	output := p.Network.Predict(encodeState(state))
+	dymension := len(state.Board)
+	sum := 0.0
+	for i, m := range moves {
+		if m.Kind == rules.PassMove {
+			priors[i] = output.At(0, dymension*dymension)
+		} else {
+			priors[i] = output.At(0, m.Y*dymension+m.X)
+		}
+		sum += priors[i]
+	}
+	for i := range priors {
+		if sum > 0 {
+			priors[i] /= sum
+		} else {
+			priors[i] = 1 / float64(len(moves))
+		}
+	}
+	return priors
+}
+
+// selectChild returns child with the highest upper confidence bound. Unvisited children are selected first
+// without network, with network the bound is weighted by the move prior.
+func (p *MCTS) selectChild(node *mctsNode) *mctsNode {
+	var best *mctsNode
+	bestValue := math.Inf(-1)
+	for _, c := range node.children {
+		var value float64
+		switch {
+		case p.Network != nil:
+			q := 0.5
+			if c.visits > 0 {
+				q = c.wins / float64(c.visits)
+			}
+			value = q + p.Exploration*c.prior*math.Sqrt(float64(node.visits))/float64(1+c.visits)
+		case c.visits == 0:
+			return c
+		default:
+			value = c.wins/float64(c.visits) + p.Exploration*math.Sqrt(math.Log(float64(node.visits))/float64(c.visits))
+		}
+		if value > bestValue {
+			best, bestValue = c, value
+		}
+	}
+	return best
+}
+
+// evaluate returns winner of the game continued from the state (true for white, nil for a draw).
+func (p *MCTS) evaluate(state *GameState) *bool {
+	if !state.Finished {
+		state = p.rollout(state)
+	}
+	score := state.Score()
+	if score == 0 {
+		return nil
+	}
+	white := score > 0
+	return &white
+}
+
+// rollout finishes the game with random moves or with network moves when NetworkRollouts is set.
+func (p *MCTS) rollout(state *GameState) *GameState {
+	if p.Network == nil || !p.NetworkRollouts {
+		return state.Playout(p.rng)
+	}
+
+	s := state.Clone()
+	dymension := len(s.Board)
+	for moves := 0; moves < 3*dymension*dymension && !s.Finished; moves++ {
+		_, suggestions, _ := interperate(p.Network.Predict(encodeState(s)), s)
+		played := false
+		for ; suggestions != nil && !played; suggestions = suggestions.Next {
+			m := suggestions.Element
+			played = !s.IsEye(m.X, m.Y, s.WhiteToMove) && s.Place(m.X, m.Y)
+		}
+		if !played {
+			s.Skip()
+		}
+	}
+	return s
+}
@@ -1,12 +1,27 @@
 package player
 
 import (
+	"context"
+
 	"github.com/al-pi314/gogo"
+	"github.com/pkg/errors"
 )
 
 type GameState = gogo.GameState
 
+type Move = gogo.Move
+
+// ErrNoMove is returned by players that did not decide on their move yet, such as humans waiting for input.
+var ErrNoMove = errors.New("player has not decided on the move yet")
+
 type Player interface {
-	Place(*gogo.GameState) (bool, *int, *int)
+	// Place returns the move of the player to move. Players stop thinking and return the context error when
+	// the context is done.
+	Place(ctx context.Context, state *GameState) (Move, error)
 	IsHuman() bool
 }
+
+// Analyser is implemented by players that can show how they rate each board position.
+type Analyser interface {
+	Policy(*GameState) ([][]float64, float64)
+}
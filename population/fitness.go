@@ -0,0 +1,150 @@
+package population
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/al-pi314/gogo/rules"
+	"github.com/pkg/errors"
+)
+
+// Outcome is a played match rated by fitness functions.
+type Outcome struct {
+	Game *rules.GameState
+	// WhiteElo and BlackElo are elo rating changes caused by the match.
+	WhiteElo float64
+	BlackElo float64
+}
+
+// Fitness rates how well both players played a match. Fitness of all matches is added to entety score which
+// selects enteties for the next round.
+type Fitness interface {
+	// Evaluate returns fitness of the white and black player.
+	Evaluate(Outcome) (float64, float64)
+}
+
+// WinLoss rates wins with 1, draws with 0.5 and losses with 0.
+type WinLoss struct{}
+
+func (WinLoss) Evaluate(o Outcome) (float64, float64) {
+	winner := o.Game.Result().Winner
+	if winner == nil {
+		return 0.5, 0.5
+	}
+	if *winner {
+		return 1, 0
+	}
+	return 0, 1
+}
+
+// ScoreMargin rates players by the game score. Games won by resignation or timeout count as won by the whole
+// board.
+type ScoreMargin struct{}
+
+func (ScoreMargin) Evaluate(o Outcome) (float64, float64) {
+	result := o.Game.Result()
+	margin := o.Game.Score()
+	if result.Reason == rules.ByResign || result.Reason == rules.ByTimeout {
+		margin = float64(len(o.Game.Board) * len(o.Game.Board))
+		if !*result.Winner {
+			margin = -margin
+		}
+	}
+	return margin, -margin
+}
+
+// PerMoveScore rates players by their score divided by number of pieces they placed, passes are not counted.
+// It rewards players that pass early.
+type PerMoveScore struct{}
+
+func (PerMoveScore) Evaluate(o Outcome) (float64, float64) {
+	_, ws, bs := o.Game.FullScore()
+	whiteMoves, blackMoves := placedPieces(o.Game)
+	whiteScorePerMoves := ws / math.Max(1, float64(whiteMoves))
+	blackScorePerMoves := bs / math.Max(1, float64(blackMoves))
+	return whiteScorePerMoves, blackScorePerMoves
+}
+
+// placedPieces returns number of pieces placed by the white and black player. Game state move counters only
+// count passes.
+func placedPieces(g *rules.GameState) (int, int) {
+	white, black := 0, 0
+	for i, m := range g.Moves {
+		if m[0] == nil || i >= len(g.MoveColors) {
+			continue
+		}
+		if g.MoveColors[i] {
+			white++
+		} else {
+			black++
+		}
+	}
+	return white, black
+}
+
+// RatingDelta rates players by their elo rating change, wins against stronger players are worth more.
+type RatingDelta struct{}
+
+func (RatingDelta) Evaluate(o Outcome) (float64, float64) {
+	return o.WhiteElo, o.BlackElo
+}
+
+// Weighted is a fitness function with its weight in a shaped fitness.
+type Weighted struct {
+	Fitness Fitness
+	Weight  float64
+}
+
+// Shaped rates players by weighted sum of fitness functions.
+type Shaped []Weighted
+
+func (s Shaped) Evaluate(o Outcome) (float64, float64) {
+	white, black := 0.0, 0.0
+	for _, w := range s {
+		ws, bs := w.Fitness.Evaluate(o)
+		white += w.Weight * ws
+		black += w.Weight * bs
+	}
+	return white, black
+}
+
+var fitnesses = map[string]Fitness{
+	"WINLOSS":     WinLoss{},
+	"SCOREMARGIN": ScoreMargin{},
+	"PERMOVE":     PerMoveScore{},
+	"RATING":      RatingDelta{},
+}
+
+// FitnessByName returns fitness function by name (WIN_LOSS, SCORE_MARGIN, PER_MOVE or RATING). Shaped fitness is
+// written as comma separated names with weights (e.g. "WIN_LOSS:1,SCORE_MARGIN:0.05"), empty name defaults to
+// per move score.
+func FitnessByName(name string) (Fitness, error) {
+	if strings.TrimSpace(name) == "" {
+		return PerMoveScore{}, nil
+	}
+
+	shaped := Shaped{}
+	for _, term := range strings.Split(name, ",") {
+		parts := strings.SplitN(term, ":", 2)
+		key := strings.ToUpper(strings.NewReplacer("_", "", "-", "", " ", "").Replace(parts[0]))
+		f, ok := fitnesses[key]
+		if !ok {
+			return nil, errors.Errorf("unknown fitness function %q", parts[0])
+		}
+		weight := 1.0
+		if len(parts) == 2 {
+			var err error
+			weight, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid fitness weight")
+			}
+		}
+		shaped = append(shaped, Weighted{Fitness: f, Weight: weight})
+	}
+
+	if len(shaped) == 1 && shaped[0].Weight == 1 {
+		return shaped[0].Fitness, nil
+	}
+	return shaped, nil
+}
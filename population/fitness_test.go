@@ -0,0 +1,113 @@
+package population
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/al-pi314/gogo/rules"
+)
+
+func TestFitnessByName(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  string
+		want  Fitness
+		valid bool
+	}{
+		{
+			name:  "default",
+			spec:  " ",
+			want:  PerMoveScore{},
+			valid: true,
+		},
+		{
+			name:  "single name",
+			spec:  "WIN_LOSS",
+			want:  WinLoss{},
+			valid: true,
+		},
+		{
+			name:  "name without separators",
+			spec:  "scoremargin",
+			want:  ScoreMargin{},
+			valid: true,
+		},
+		{
+			name:  "single name with weight",
+			spec:  "RATING:0.5",
+			want:  Shaped{{Fitness: RatingDelta{}, Weight: 0.5}},
+			valid: true,
+		},
+		{
+			name:  "single name with unit weight",
+			spec:  "per-move:1",
+			want:  PerMoveScore{},
+			valid: true,
+		},
+		{
+			name: "weighted names",
+			spec: "WIN_LOSS:1, score_margin : 0.05,RATING",
+			want: Shaped{
+				{Fitness: WinLoss{}, Weight: 1},
+				{Fitness: ScoreMargin{}, Weight: 0.05},
+				{Fitness: RatingDelta{}, Weight: 1},
+			},
+			valid: true,
+		},
+		{
+			name:  "negative weight",
+			spec:  "WIN_LOSS:2,PER_MOVE:-0.5",
+			want:  Shaped{{Fitness: WinLoss{}, Weight: 2}, {Fitness: PerMoveScore{}, Weight: -0.5}},
+			valid: true,
+		},
+		{
+			name: "unknown name",
+			spec: "WIN_LOSS:1,ELO:1",
+		},
+		{
+			name: "invalid weight",
+			spec: "WIN_LOSS:one",
+		},
+		{
+			name: "empty term",
+			spec: "WIN_LOSS,",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := FitnessByName(test.spec)
+			if !test.valid {
+				if err == nil {
+					t.Fatalf("expected error, got fitness %#v", f)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse fitness: %v", err)
+			}
+			if !reflect.DeepEqual(f, test.want) {
+				t.Fatalf("fitness %#v, expected %#v", f, test.want)
+			}
+		})
+	}
+}
+
+func TestShaped(t *testing.T) {
+	// black places a piece and both players pass, black wins by the whole board without komi
+	g := rules.NewGameState(3, rules.Settings{})
+	for _, m := range []rules.Move{rules.Play(1, 1), rules.Pass(), rules.Pass()} {
+		g.Apply(m)
+	}
+	o := Outcome{Game: g, WhiteElo: -10, BlackElo: 10}
+
+	f, err := FitnessByName("WIN_LOSS:1,SCORE_MARGIN:0.5,RATING:0.1,PER_MOVE:2")
+	if err != nil {
+		t.Fatalf("failed to parse fitness: %v", err)
+	}
+	// black placed one piece so per move score is its whole score, white placed none
+	white, black := f.Evaluate(o)
+	if white != 0-4.5-1+0 || black != 1+4.5+1+18 {
+		t.Fatalf("fitness %f and %f, expected %f and %f", white, black, 0-4.5-1+0.0, 1+4.5+1+18.0)
+	}
+}
@@ -0,0 +1,194 @@
+package population
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+
+	"github.com/al-pi314/gogo/player"
+	"github.com/pkg/errors"
+)
+
+// ---- Hall Of Fame ---- \\
+
+// archiveBest adds copy of the best rated entety to the hall of fame. Archived enteties are not changed by
+// later rounds. When the hall of fame has more than size members the oldest are removed, zero size keeps all.
+func (p *Population) archiveBest(size int) {
+	best := *p.Leaderboard()[0]
+	agent := best.copyAgent()
+	best.Agent = agent
+	p.HallOfFame = append(p.HallOfFame, &best)
+	if size > 0 && len(p.HallOfFame) > size {
+		p.HallOfFame = p.HallOfFame[len(p.HallOfFame)-size:]
+	}
+}
+
+// sampleHallOfFame returns up to n different hall of fame members.
+func (p *Population) sampleHallOfFame(n int, rng *rand.Rand) []*Entety {
+	sample := []*Entety{}
+	for _, i := range rng.Perm(len(p.HallOfFame)) {
+		if len(sample) >= n {
+			break
+		}
+		sample = append(sample, p.HallOfFame[i])
+	}
+	return sample
+}
+
+// ---- Benchmark ---- \\
+
+// BenchmarkSettings configure evaluation of the best agents played after each round.
+type BenchmarkSettings struct {
+	// Agents is number of the best rated agents evaluated, zero disables the evaluation.
+	Agents int
+	// HallOfFame is number of sampled hall of fame members each agent plays against.
+	HallOfFame int
+	// Games is number of games played against each opponent, colors are alternated.
+	Games int
+}
+
+// opponent is a benchmark opponent creating a new player for every game.
+type opponent struct {
+	name string
+	// member is the hall of fame entety, nil for baseline players.
+	member *Entety
+	player func(seed int64) player.Player
+}
+
+// plays returns wether the opponent is benchmarked against the entety. Hall of fame members do not play against
+// their own copies.
+func (o opponent) plays(e *Entety) bool {
+	return o.member == nil || !o.member.Agent.Logic.Equal(e.Agent.Logic)
+}
+
+// benchmarkGame is benchmark match played by an evaluated agent.
+type benchmarkGame struct {
+	*match
+	opponent   int
+	agentWhite bool
+}
+
+// benchmarkOpponents returns baseline players and sampled hall of fame members.
+func (p *Population) benchmarkOpponents(settings BenchmarkSettings, rng *rand.Rand) []opponent {
+	opponents := []opponent{}
+	for _, name := range player.BaselineNames() {
+		name := name
+		opponents = append(opponents, opponent{
+			name: name,
+			player: func(seed int64) player.Player {
+				p, _ := player.NewBaseline(name, seed)
+				return p
+			},
+		})
+	}
+	for _, e := range p.sampleHallOfFame(settings.HallOfFame, rng) {
+		e := e
+		opponents = append(opponents, opponent{
+			name:   fmt.Sprintf("hall of fame (born %d)", e.Born),
+			member: e,
+			player: func(int64) player.Player {
+				return e.copyAgent()
+			},
+		})
+	}
+	return opponents
+}
+
+// benchmark plays the best rated agents against baseline players and hall of fame members and logs their win
+// rates to the output directory.
+func (p *Population) benchmark(settings BenchmarkSettings, workers int, seed int64) {
+	if settings.Agents <= 0 {
+		return
+	}
+	rng := rand.New(rand.NewSource(seed))
+	opponents := p.benchmarkOpponents(settings, rng)
+
+	games := []*benchmarkGame{}
+	matches := []*match{}
+	leaderboard := p.Leaderboard()
+	if settings.Agents < len(leaderboard) {
+		leaderboard = leaderboard[:settings.Agents]
+	}
+	for _, e := range leaderboard {
+		e := e
+		for oi, o := range opponents {
+			o := o
+			if !o.plays(e) {
+				continue
+			}
+			for i := 0; i < settings.Games; i++ {
+				g := &benchmarkGame{
+					match:      &match{},
+					opponent:   oi,
+					agentWhite: i%2 == 0,
+				}
+				g.players = func(seed int64) (player.Player, player.Player) {
+					if g.agentWhite {
+						return e.copyAgent(), o.player(seed)
+					}
+					return o.player(seed), e.copyAgent()
+				}
+				games = append(games, g)
+				matches = append(matches, g.match)
+			}
+		}
+	}
+
+	// baseline players pass only when no moves outside of their eyes are left so long games are stopped at the move limit
+	gameRules := p.GameRules
+	if gameRules.MoveLimit <= 0 {
+		gameRules.MoveLimit = 4 * p.GameDymension * p.GameDymension
+	}
+	p.playMatches(matches, gameRules, workers, rng.Int63())
+
+	// count wins by opponent
+	played := make([]int, len(opponents))
+	wins := make([]float64, len(opponents))
+	for _, g := range games {
+		score := g.score()
+		if !g.agentWhite {
+			score = 1 - score
+		}
+		played[g.opponent]++
+		wins[g.opponent] += score
+	}
+
+	lines := []string{}
+	for i, o := range opponents {
+		if played[i] == 0 {
+			fmt.Printf("[benchmark] no games against %s (same as benchmarked agents)\n", o.name)
+			continue
+		}
+		rate := wins[i] / float64(played[i])
+		fmt.Printf("[benchmark] win rate against %s %.2f (%d games)\n", o.name, rate, played[i])
+		// hall of fame members are logged with the population age they were born at
+		name, born := o.name, ""
+		if o.member != nil {
+			name, born = "hall of fame", strconv.Itoa(o.member.Born)
+		}
+		lines = append(lines, fmt.Sprintf("%d,%s,%s,%d,%f\n", p.Age, name, born, played[i], rate))
+	}
+	p.logBenchmark(lines)
+}
+
+// logBenchmark appends lines to benchmark file in the output directory.
+func (p *Population) logBenchmark(lines []string) {
+	file, err := os.OpenFile(fmt.Sprintf("%s/benchmark.csv", p.OutputDirectory), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0755)
+	if err != nil {
+		log.Print(errors.Wrap(err, "failed to open benchmark output file"))
+		return
+	}
+	defer file.Close()
+
+	if stat, err := file.Stat(); err == nil && stat.Size() == 0 {
+		lines = append([]string{"age,opponent,opponent_born,games,win_rate\n"}, lines...)
+	}
+	for _, line := range lines {
+		if _, err := file.WriteString(line); err != nil {
+			log.Print(errors.Wrap(err, "failed to write benchmark results"))
+			return
+		}
+	}
+}
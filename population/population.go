@@ -7,20 +7,26 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"reflect"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/al-pi314/gogo"
-	"github.com/al-pi314/gogo/game"
 	"github.com/al-pi314/gogo/nn"
 	"github.com/al-pi314/gogo/player"
+	"github.com/al-pi314/gogo/rating"
+	"github.com/al-pi314/gogo/record"
+	"github.com/al-pi314/gogo/rules"
 	"github.com/pkg/errors"
 )
 
 type Population struct {
-	GameDymension   int
-	Enteties        []*Entety
+	GameDymension int
+	GameRules     rules.Settings
+	Enteties      []*Entety
+	// HallOfFame keeps the best rated entety of every few rounds.
+	HallOfFame      []*Entety
 	Age             int
 	Size            int
 	OutputDirectory string
@@ -32,6 +38,21 @@ type Population struct {
 type Entety struct {
 	Agent *player.Agent
 	Score float64
+	// Born is population age at which the entety was created.
+	Born   int
+	Elo    rating.Elo
+	Glicko rating.Glicko
+}
+
+// NewEntety sets initial ratings of enteties without them (new enteties or enteties saved without ratings).
+func NewEntety(e Entety) *Entety {
+	if e.Elo.Rating == 0 {
+		e.Elo = rating.NewElo()
+	}
+	if e.Glicko.Deviation == 0 {
+		e.Glicko = rating.NewGlicko()
+	}
+	return &e
 }
 
 type TrainingSave struct {
@@ -39,15 +60,29 @@ type TrainingSave struct {
 	Population *Population
 }
 
+// RulesFromConfig returns game rules set in the config.
+func RulesFromConfig(config *gogo.Config) rules.Settings {
+	return rules.Settings{
+		Superko:      rules.SuperkoByName(config.Superko),
+		Ruleset:      rules.RulesetByName(config.Ruleset),
+		Komi:         config.Komi,
+		Handicap:     config.Handicap,
+		FreeHandicap: config.FreeHandicap,
+		MoveLimit:    config.MoveLimit,
+	}
+}
+
 func NewPopulation(config *gogo.Config) *Population {
 	p := Population{
 		GameDymension: config.Dymension,
+		GameRules:     RulesFromConfig(config),
 		Enteties:      []*Entety{},
 	}
 	for len(p.Enteties) < config.PopulationSize {
 		agent := player.NewAgent(player.Agent{
 			StabilizationRate: config.StabilizationRate,
 			MutationRate:      config.MutationRate,
+			ResignMargin:      config.ResignMargin,
 			Logic: nn.NewNeuralNetwork(nn.NeuralNetwork{
 				Structure: nn.Structure{
 					InputNeurons:         3*config.Dymension*config.Dymension + gogo.GameStateSize(),
@@ -57,9 +92,9 @@ func NewPopulation(config *gogo.Config) *Population {
 				ActivationFuncName: config.Activation,
 			}),
 		})
-		p.AddEntety(&Entety{
+		p.AddEntety(NewEntety(Entety{
 			Agent: &agent,
-		})
+		}))
 	}
 
 	p.CreateFiles(config.OutputDirectory)
@@ -91,12 +126,22 @@ func (p *Population) LoadFromFile(filePath *string) bool {
 		return false
 	}
 
-	for _, e := range saveData.Population.Enteties {
+	for i, e := range saveData.Population.Enteties {
+		agent := player.NewAgent(*e.Agent)
+		e.Agent = &agent
+		saveData.Population.Enteties[i] = NewEntety(*e)
+	}
+	for _, e := range saveData.Population.HallOfFame {
 		agent := player.NewAgent(*e.Agent)
 		e.Agent = &agent
 	}
 
 	fmt.Printf("...loaded population from file (population saved at %s)\n", saveData.Time.String())
+	// configured rules are kept, agents can play with different rules than they were trained with
+	if !reflect.DeepEqual(saveData.Population.GameRules, p.GameRules) {
+		fmt.Printf("WARRNING: population was saved with rules %+v, using configured rules %+v\n", saveData.Population.GameRules, p.GameRules)
+	}
+	saveData.Population.GameRules = p.GameRules
 	*p = *saveData.Population
 	return true
 }
@@ -149,24 +194,72 @@ type TrainingSettings struct {
 
 	SaveInterval     int
 	SaveGameInterval int
+
+	// Workers is number of matches played concurrently, zero uses all available cores.
+	Workers int
+	// Seed is used to derive seeds of matches played each round.
+	Seed int64
+	// Leaderboard is number of the best rated enteties printed after each round.
+	Leaderboard int
+	// HallOfFameInterval archives the best rated entety every N population ages, zero archives every round.
+	HallOfFameInterval int
+	// HallOfFameSize limits number of archived enteties saved with the population, zero keeps all.
+	HallOfFameSize int
+	Benchmark      BenchmarkSettings
+	// Fitness rates matches, entety scores select enteties for the next round.
+	Fitness Fitness
 }
 
 func (p *Population) Train(settings TrainingSettings) {
+	if settings.Fitness == nil {
+		settings.Fitness = PerMoveScore{}
+	}
 	for i := 0; i < settings.Rounds; i++ {
 		s := time.Now().UnixMilli()
 		fmt.Println("-------------------------------------")
 		fmt.Printf("starting round (population age %d) %d\n", p.Age, i)
 
+		// scores only select enteties of the round, survivors do not keep fitness of earlier rounds
+		for _, e := range p.Enteties {
+			e.Score = 0
+		}
+
 		// divide population into groups
 		groups := p.CreateGroups(settings.Groups)
 
-		// play games among agents inside groups and select top N
+		// play games among agents inside groups
+		groupsMatches := [][]*match{}
+		matches := []*match{}
+		for _, group := range groups {
+			groupsMatches = append(groupsMatches, groupMatches(group))
+			matches = append(matches, groupsMatches[len(groupsMatches)-1]...)
+		}
+		ms := time.Now().UnixMilli()
+		p.playMatches(matches, p.GameRules, settings.Workers, settings.Seed+int64(p.Age))
+		fmt.Printf("played %d matches (miliseconds spent %d)\n", len(matches), time.Now().UnixMilli()-ms)
+
+		// results are added to enteties on a single goroutine and in scheduling order
+		for _, m := range matches {
+			m.record(settings.Fitness)
+		}
+		rateGlicko(matches)
+
+		// select top N agents of every group
 		groupsBest := [][]*Entety{}
 		saveBestGames := (i+1)%settings.SaveGameInterval == 0
 		for i, group := range groups {
-			groupsBest = append(groupsBest, p.playMatches(i, group, settings.SelectBestInGroup, saveBestGames))
+			groupsBest = append(groupsBest, p.selectBest(i, group, groupsMatches[i], settings.SelectBestInGroup, saveBestGames))
 			fmt.Println("-------------")
 		}
+		if settings.Leaderboard > 0 {
+			p.PrintLeaderboard(settings.Leaderboard)
+		}
+
+		// evaluate the best agents against fixed opponents before they join them
+		p.benchmark(settings.Benchmark, settings.Workers, settings.Seed-int64(p.Age)-1)
+		if settings.HallOfFameInterval <= 1 || p.Age%settings.HallOfFameInterval == 0 {
+			p.archiveBest(settings.HallOfFameSize)
+		}
 
 		// crossover and mutate group winners to create new population
 		p.newPopulation(groupsBest, settings.SelectBestInGroup, settings.KeepBestInGroup)
@@ -195,32 +288,46 @@ func (p *Population) CreateGroups(groups int) [][]*Entety {
 	return result
 }
 
-func (p *Population) playMatches(groupID int, enteties []*Entety, toKeep int, saveBest bool) []*Entety {
-	s := time.Now().UnixMilli()
-	fmt.Printf("[group %d] starting group matches\n", groupID)
-	var best *float64
-	var bestGame game.Game
-	gameName := ""
+// groupMatches schedules a game between every ordered pair of enteties in the group.
+func groupMatches(enteties []*Entety) []*match {
+	for _, e := range enteties {
+		e.Agent.IllegalSuggestions = 0
+	}
+	matches := []*match{}
 	for idOne, entetyOne := range enteties {
 		for idTwo, entetyTwo := range enteties {
 			if idOne == idTwo {
 				continue
 			}
-			score, whiteScore, blackScore, game := entetyOne.match(entetyTwo, p.GameDymension)
-			entetyOne.Score += whiteScore
-			entetyTwo.Score += blackScore
-
-			abs_score := math.Abs(score)
-			if best == nil || *best > abs_score {
-				best = &abs_score
-				bestGame = *game
-				gameName = fmt.Sprintf("group_%d_%d_%d_%d_%d.json", p.Age, groupID, idOne, idTwo, int(*best))
-			}
+			matches = append(matches, &match{
+				white:   entetyOne,
+				black:   entetyTwo,
+				whiteID: idOne,
+				blackID: idTwo,
+			})
+		}
+	}
+	return matches
+}
+
+func (p *Population) selectBest(groupID int, enteties []*Entety, matches []*match, toKeep int, saveBest bool) []*Entety {
+	var best *float64
+	var bestGame *rules.GameState
+	gameName := ""
+	whiteName, blackName := "", ""
+	for _, m := range matches {
+		abs_score := math.Abs(m.game.Score())
+		if best == nil || *best > abs_score {
+			best = &abs_score
+			bestGame = m.game
+			gameName = fmt.Sprintf("group_%d_%d_%d_%d_%d.sgf", p.Age, groupID, m.whiteID, m.blackID, int(*best))
+			whiteName = fmt.Sprintf("agent %d (group %d, age %d)", m.whiteID, groupID, p.Age)
+			blackName = fmt.Sprintf("agent %d (group %d, age %d)", m.blackID, groupID, p.Age)
 		}
 	}
-	if saveBest {
-		bestGame.SaveFileName = fmt.Sprintf("%s/games/%s", p.OutputDirectory, gameName)
-		bestGame.Save()
+	// groups with a single entety play no matches
+	if saveBest && bestGame != nil {
+		record.Save(fmt.Sprintf("%s/games/%s", p.OutputDirectory, gameName), record.FromState(bestGame, whiteName, blackName))
 	}
 
 	sort.Slice(enteties, func(i, j int) bool {
@@ -228,7 +335,11 @@ func (p *Population) playMatches(groupID int, enteties []*Entety, toKeep int, sa
 	})
 	fmt.Printf("[group %d] best entety score %f\n", groupID, enteties[0].Score)
 	fmt.Printf("[group %d] worst entety score %f\n", groupID, enteties[len(enteties)-1].Score)
-	fmt.Printf("[group %d] finished group matches (miliseconds spent %d)\n", groupID, time.Now().UnixMilli()-s)
+	illegal := 0
+	for _, e := range enteties {
+		illegal += e.Agent.IllegalSuggestions
+	}
+	fmt.Printf("[group %d] moves with illegal best suggestion %d\n", groupID, illegal)
 	return enteties[:toKeep]
 }
 
@@ -253,10 +364,10 @@ func (p *Population) newPopulation(groups [][]*Entety, groupsSize int, keepTopIn
 		entetyTwo := groups[rand.Intn(len(groups))][rand.Intn(groupsSize)]
 
 		// crossover & mutate for new entety
-		newEntety := Entety{
+		p.AddEntety(NewEntety(Entety{
 			Agent: entetyOne.Agent.Crossover(entetyTwo.Agent),
-		}
-		p.AddEntety(&newEntety)
+			Born:  p.Age + 1,
+		}))
 
 		// select next entety
 		groupIdx++
@@ -276,21 +387,3 @@ func (p *Population) FirstNthAgent(n int) *player.Agent {
 
 	return p.Enteties[n].Agent
 }
-
-func (e *Entety) match(o *Entety, gameDymension int) (float64, float64, float64, *game.Game) {
-	g := game.NewGame(game.Game{
-		Dymension:   gameDymension,
-		WhitePlayer: e.Agent,
-		BlackPlayer: o.Agent,
-	})
-
-	for g.Update() == nil {
-		// play game
-	}
-
-	gameScore, ws, bs := g.FullScore()
-	_, wm, bm := g.FullMoves()
-	whiteScorePerMoves := ws / math.Max(1, float64(wm))
-	blackScorePerMoves := bs / math.Max(1, float64(bm))
-	return gameScore, whiteScorePerMoves, blackScorePerMoves, g
-}
@@ -0,0 +1,67 @@
+package population
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/al-pi314/gogo/rating"
+)
+
+// score returns game score of the white player used by ratings (1 for a win, 0.5 for a draw and 0 for a loss).
+func (m *match) score() float64 {
+	winner := m.game.Result().Winner
+	if winner == nil {
+		return 0.5
+	}
+	if *winner {
+		return 1
+	}
+	return 0
+}
+
+// rateElo updates elo ratings of enteties that played the match and returns their rating changes.
+func (m *match) rateElo() (float64, float64) {
+	score := m.score()
+	white, black := m.white.Elo, m.black.Elo
+	m.white.Elo.Update(black, score)
+	m.black.Elo.Update(white, 1-score)
+	return m.white.Elo.Rating - white.Rating, m.black.Elo.Rating - black.Rating
+}
+
+// rateGlicko updates glicko ratings of enteties that played the matches against each other once for all of
+// the matches.
+func rateGlicko(matches []*match) {
+	results := map[*Entety][]rating.Result{}
+	for _, m := range matches {
+		if m.white == nil || m.black == nil {
+			continue
+		}
+		score := m.score()
+		results[m.white] = append(results[m.white], rating.Result{Opponent: m.black.Glicko, Score: score})
+		results[m.black] = append(results[m.black], rating.Result{Opponent: m.white.Glicko, Score: 1 - score})
+	}
+	for e, r := range results {
+		e.Glicko = e.Glicko.Update(r)
+	}
+}
+
+// Leaderboard returns enteties ordered by glicko rating, uncertain ratings are ranked lower.
+func (p *Population) Leaderboard() []*Entety {
+	leaderboard := append([]*Entety{}, p.Enteties...)
+	sort.SliceStable(leaderboard, func(i, j int) bool {
+		return leaderboard[i].Glicko.Lower() > leaderboard[j].Glicko.Lower()
+	})
+	return leaderboard
+}
+
+// PrintLeaderboard prints ratings of the best n enteties, all enteties are printed when n is not positive.
+func (p *Population) PrintLeaderboard(n int) {
+	leaderboard := p.Leaderboard()
+	if n <= 0 || n > len(leaderboard) {
+		n = len(leaderboard)
+	}
+	fmt.Printf("%4s %6s %16s %6s %6s\n", "rank", "born", "glicko", "elo", "games")
+	for i, e := range leaderboard[:n] {
+		fmt.Printf("%4d %6d %8.1f ± %5.1f %6.1f %6d\n", i+1, e.Born, e.Glicko.Rating, 2*e.Glicko.Deviation, e.Elo.Rating, e.Elo.Games)
+	}
+}
@@ -0,0 +1,114 @@
+package population
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"github.com/al-pi314/gogo/player"
+	"github.com/al-pi314/gogo/rules"
+	"github.com/pkg/errors"
+)
+
+// match is a single game scheduled on the worker pool. Game fields are written only by the worker playing the
+// match and read once all scheduled matches are played.
+type match struct {
+	// white and black are population enteties, nil for players from outside of the population.
+	white, black *Entety
+	// whiteID and blackID are indexes of the players inside of their group.
+	whiteID, blackID int
+	// players creates players of the match, enteties play with copies of their agents when it is not set.
+	players func(seed int64) (player.Player, player.Player)
+
+	seed                     int64
+	game                     *rules.GameState
+	whitePlayer, blackPlayer player.Player
+}
+
+// copyAgent returns agent copy that can play concurrently with other copies. Networks are only read while
+// playing so they are shared.
+func (e *Entety) copyAgent() *player.Agent {
+	agent := *e.Agent
+	agent.SuggestedOnMove = -1
+	agent.SuggestedMoves = nil
+	agent.IllegalSuggestions = 0
+	return &agent
+}
+
+// playMatches plays matches with the game rules on the pool of workers, zero workers uses one worker per
+// available core. Seeds are drawn in scheduling order so games do not depend on number of workers.
+func (p *Population) playMatches(matches []*match, gameRules rules.Settings, workers int, seed int64) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	seeds := rand.New(rand.NewSource(seed))
+	for _, m := range matches {
+		m.seed = seeds.Int63()
+	}
+
+	queue := make(chan *match)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for m := range queue {
+				m.play(p.GameDymension, gameRules)
+			}
+		}()
+	}
+	for _, m := range matches {
+		queue <- m
+	}
+	close(queue)
+	wg.Wait()
+}
+
+func (m *match) play(gameDymension int, gameRules rules.Settings) {
+	if m.players != nil {
+		m.whitePlayer, m.blackPlayer = m.players(m.seed)
+	} else {
+		m.whitePlayer, m.blackPlayer = m.white.copyAgent(), m.black.copyAgent()
+	}
+
+	g := rules.NewGameState(gameDymension, gameRules)
+	for !g.Finished {
+		// play game
+		player := m.whitePlayer
+		if !g.WhiteToMove {
+			player = m.blackPlayer
+		}
+
+		move, err := player.Place(context.Background(), g)
+		if err != nil {
+			log.Print(errors.Wrap(err, "player failed to make a move"))
+			move = rules.Pass()
+		}
+		g.Apply(move)
+	}
+	m.game = g
+}
+
+// record adds match fitness to the enteties that played it and updates their elo ratings. Matches are
+// recorded on a single goroutine and in scheduling order.
+func (m *match) record(fitness Fitness) {
+	outcome := Outcome{Game: m.game}
+	if m.white != nil && m.black != nil {
+		outcome.WhiteElo, outcome.BlackElo = m.rateElo()
+	}
+	whiteScore, blackScore := fitness.Evaluate(outcome)
+	if m.white != nil {
+		m.white.Score += whiteScore
+		if a, ok := m.whitePlayer.(*player.Agent); ok {
+			m.white.Agent.IllegalSuggestions += a.IllegalSuggestions
+		}
+	}
+	if m.black != nil {
+		m.black.Score += blackScore
+		if a, ok := m.blackPlayer.(*player.Agent); ok {
+			m.black.Agent.IllegalSuggestions += a.IllegalSuggestions
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package rating
+
+import "math"
+
+const (
+	// InitialRating is rating of players without games, shared by elo and glicko ratings.
+	InitialRating = 1500.0
+	// EloK is the largest elo change a single game can make.
+	EloK = 32.0
+)
+
+// Elo rating is updated after every game.
+type Elo struct {
+	Rating float64
+	Games  int
+}
+
+func NewElo() Elo {
+	return Elo{Rating: InitialRating}
+}
+
+// Expected returns expected score of a player with the rating against the opponent rating.
+func Expected(rating, opponent float64) float64 {
+	return 1 / (1 + math.Pow(10, (opponent-rating)/400))
+}
+
+// Update changes the rating by game score against the opponent (1 for a win, 0.5 for a draw and 0 for a
+// loss).
+func (e *Elo) Update(opponent Elo, score float64) {
+	e.Rating += EloK * (score - Expected(e.Rating, opponent.Rating))
+	e.Games++
+}
@@ -0,0 +1,65 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEloUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		rating   float64
+		opponent float64
+		score    float64
+		want     float64
+	}{
+		{
+			name:     "win against equal rating",
+			rating:   1500,
+			opponent: 1500,
+			score:    1,
+			want:     1516,
+		},
+		{
+			name:     "draw against equal rating",
+			rating:   1500,
+			opponent: 1500,
+			score:    0.5,
+			want:     1500,
+		},
+		{
+			name:     "loss against equal rating",
+			rating:   1500,
+			opponent: 1500,
+			score:    0,
+			want:     1484,
+		},
+		{
+			name:     "win against stronger player",
+			rating:   1500,
+			opponent: 1900,
+			score:    1,
+			want:     1500 + EloK*10/11,
+		},
+		{
+			name:     "loss against weaker player",
+			rating:   1900,
+			opponent: 1500,
+			score:    0,
+			want:     1900 - EloK*10/11,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := Elo{Rating: test.rating, Games: 3}
+			e.Update(Elo{Rating: test.opponent}, test.score)
+			if math.Abs(e.Rating-test.want) > 1e-9 {
+				t.Fatalf("rating %f, expected %f", e.Rating, test.want)
+			}
+			if e.Games != 4 {
+				t.Fatalf("games %d, expected 4", e.Games)
+			}
+		})
+	}
+}
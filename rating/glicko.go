@@ -0,0 +1,109 @@
+package rating
+
+import "math"
+
+const (
+	// InitialDeviation is glicko rating deviation of players without games.
+	InitialDeviation = 350.0
+	// InitialVolatility is glicko volatility of players without games.
+	InitialVolatility = 0.06
+	// Tau limits volatility changes between rating periods.
+	Tau = 0.5
+
+	glickoScale     = 173.7178
+	glickoTolerance = 0.000001
+)
+
+// Glicko is glicko-2 rating. Ratings are updated once per rating period from all games of the period.
+type Glicko struct {
+	Rating     float64
+	Deviation  float64
+	Volatility float64
+}
+
+func NewGlicko() Glicko {
+	return Glicko{
+		Rating:     InitialRating,
+		Deviation:  InitialDeviation,
+		Volatility: InitialVolatility,
+	}
+}
+
+// Result is score of a game in the rating period against the opponent rating at the start of the period.
+type Result struct {
+	Opponent Glicko
+	Score    float64
+}
+
+// Lower returns lower bound of the rating 95% confidence interval, uncertain ratings are ranked lower.
+func (g Glicko) Lower() float64 {
+	return g.Rating - 2*g.Deviation
+}
+
+func (g Glicko) scaled() (float64, float64) {
+	return (g.Rating - InitialRating) / glickoScale, g.Deviation / glickoScale
+}
+
+func impact(deviation float64) float64 {
+	return 1 / math.Sqrt(1+3*deviation*deviation/(math.Pi*math.Pi))
+}
+
+// Update returns rating after the rating period with the results. Deviation of players without games grows.
+func (g Glicko) Update(results []Result) Glicko {
+	mu, phi := g.scaled()
+	if len(results) == 0 {
+		g.Deviation = math.Sqrt(phi*phi+g.Volatility*g.Volatility) * glickoScale
+		return g
+	}
+
+	// estimated variance and improvement
+	variance, improvement := 0.0, 0.0
+	for _, r := range results {
+		opponentMu, opponentPhi := r.Opponent.scaled()
+		gPhi := impact(opponentPhi)
+		expected := 1 / (1 + math.Exp(-gPhi*(mu-opponentMu)))
+		variance += gPhi * gPhi * expected * (1 - expected)
+		improvement += gPhi * (r.Score - expected)
+	}
+	variance = 1 / variance
+	delta := variance * improvement
+
+	// new volatility is found with the illinois algorithm
+	a := math.Log(g.Volatility * g.Volatility)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + variance + ex
+		return ex*(delta*delta-d)/(2*d*d) - (x-a)/(Tau*Tau)
+	}
+	A, B := a, 0.0
+	if delta*delta > phi*phi+variance {
+		B = math.Log(delta*delta - phi*phi - variance)
+	} else {
+		k := 1.0
+		for f(a-k*Tau) < 0 {
+			k++
+		}
+		B = a - k*Tau
+	}
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glickoTolerance {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	volatility := math.Exp(A / 2)
+
+	phiStar := math.Sqrt(phi*phi + volatility*volatility)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/variance)
+	newMu := mu + newPhi*newPhi*improvement
+	return Glicko{
+		Rating:     newMu*glickoScale + InitialRating,
+		Deviation:  newPhi * glickoScale,
+		Volatility: volatility,
+	}
+}
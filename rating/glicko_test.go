@@ -0,0 +1,57 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGlickoUpdate(t *testing.T) {
+	player := Glicko{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	tests := []struct {
+		name    string
+		player  Glicko
+		results []Result
+		want    Glicko
+		// tolerance of rating and deviation, volatility is compared to a thousandth of it
+		tolerance float64
+	}{
+		{
+			// example from the glicko-2 paper by Mark Glickman
+			name:   "rating period from the paper",
+			player: player,
+			results: []Result{
+				{Opponent: Glicko{Rating: 1400, Deviation: 30}, Score: 1},
+				{Opponent: Glicko{Rating: 1550, Deviation: 100}, Score: 0},
+				{Opponent: Glicko{Rating: 1700, Deviation: 300}, Score: 0},
+			},
+			want:      Glicko{Rating: 1464.06, Deviation: 151.52, Volatility: 0.05999},
+			tolerance: 0.01,
+		},
+		{
+			name:      "rating period without games",
+			player:    player,
+			want:      Glicko{Rating: 1500, Deviation: math.Sqrt(200*200 + math.Pow(0.06*glickoScale, 2)), Volatility: 0.06},
+			tolerance: 1e-9,
+		},
+		{
+			name:   "win of a new player",
+			player: NewGlicko(),
+			results: []Result{
+				{Opponent: NewGlicko(), Score: 1},
+			},
+			want:      Glicko{Rating: 1662.31, Deviation: 290.32, Volatility: 0.06},
+			tolerance: 0.01,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := test.player.Update(test.results)
+			if math.Abs(g.Rating-test.want.Rating) > test.tolerance ||
+				math.Abs(g.Deviation-test.want.Deviation) > test.tolerance ||
+				math.Abs(g.Volatility-test.want.Volatility) > test.tolerance/1000 {
+				t.Fatalf("rating %+v, expected %+v", g, test.want)
+			}
+		})
+	}
+}
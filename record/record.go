@@ -0,0 +1,150 @@
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/al-pi314/gogo/clock"
+	"github.com/al-pi314/gogo/rules"
+	"github.com/pkg/errors"
+)
+
+// GameSave is the legacy json game save format.
+type GameSave struct {
+	Time   *time.Time
+	Moves  [][2]*int
+	Result *rules.Result `json:",omitempty"`
+	// TimeControl is set for games played with time control.
+	TimeControl *clock.Settings `json:",omitempty"`
+}
+
+// Record holds game information stored in game save files.
+type Record struct {
+	Time      *time.Time
+	Dymension int
+	Komi      float64
+	Handicap  int
+	Ruleset   rules.Ruleset
+	WhiteName string
+	BlackName string
+	Setup     []rules.Cordinate
+	Moves     [][2]*int
+	// MoveColors holds color of the player that made each move (true for white).
+	MoveColors []bool
+	// Result is set for finished games.
+	Result      *rules.Result
+	TimeControl clock.Settings
+}
+
+// FromState creates game record from the game state.
+func FromState(state *rules.GameState, whiteName, blackName string) Record {
+	now := time.Now()
+	r := Record{
+		Time:       &now,
+		Dymension:  len(state.Board),
+		Komi:       state.Settings.Komi,
+		Handicap:   state.Settings.Handicap,
+		Ruleset:    state.Settings.Ruleset,
+		WhiteName:  whiteName,
+		BlackName:  blackName,
+		Setup:      state.Setup,
+		Moves:      state.Moves,
+		MoveColors: state.MoveColors,
+	}
+	if state.Finished {
+		result := state.Result()
+		r.Result = &result
+	}
+	return r
+}
+
+// Settings returns rules settings required to replay the recorded game.
+func (r Record) Settings() rules.Settings {
+	settings := rules.Settings{
+		Ruleset:       r.Ruleset,
+		Komi:          r.Komi,
+		Handicap:      r.Handicap,
+		HandicapSetup: r.Setup,
+	}
+	// handicap without setup pieces was placed with moves
+	if r.Handicap > 1 && len(r.Setup) == 0 {
+		settings.FreeHandicap = true
+	}
+	return settings
+}
+
+// Save writes game record into the game save file. Files with .sgf extension are written in SGF format,
+// others in the legacy json format.
+func Save(filePath string, r Record) {
+	var data []byte
+	if strings.EqualFold(filepath.Ext(filePath), ".sgf") {
+		buf := bytes.Buffer{}
+		if err := r.WriteSGF(&buf); err != nil {
+			log.Fatal(errors.Wrap(err, "could not write sgf game"))
+		}
+		data = buf.Bytes()
+	} else {
+		gameSave := GameSave{
+			Time:   r.Time,
+			Moves:  r.Moves,
+			Result: r.Result,
+		}
+		if r.TimeControl.System != clock.NoTime {
+			gameSave.TimeControl = &r.TimeControl
+		}
+		var err error
+		data, err = json.Marshal(gameSave)
+		if err != nil {
+			log.Fatal(errors.Wrap(err, "could not marshal game"))
+		}
+	}
+
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0755)
+	if err != nil {
+		log.Print(errors.Wrap(err, fmt.Sprintf("failed to open game output file %q", filePath)))
+		return
+	}
+	defer file.Close()
+
+	n, err := file.Write(data)
+	if err != nil || n != len(data) {
+		log.Fatal(errors.Wrap(err, fmt.Sprintf("writting error or the write was incomplete (attempted to write %d bytes, written %d bytes)", len(data), n)))
+	}
+	fmt.Println("saved game!")
+}
+
+// Load reads game save file in SGF or the legacy json format. Legacy saves do not record board dymension.
+func Load(filePath string) Record {
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "failed to open game file"))
+	}
+
+	if strings.EqualFold(filepath.Ext(filePath), ".sgf") {
+		r, err := ReadSGF(raw)
+		if err != nil {
+			log.Fatal(errors.Wrap(err, "failed to load sgf game file"))
+		}
+		return r
+	}
+
+	gameSave := GameSave{}
+	if err := json.Unmarshal(raw, &gameSave); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to load game save file"))
+	}
+	r := Record{
+		Time:   gameSave.Time,
+		Moves:  gameSave.Moves,
+		Result: gameSave.Result,
+	}
+	if gameSave.TimeControl != nil {
+		r.TimeControl = *gameSave.TimeControl
+	}
+	return r
+}
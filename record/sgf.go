@@ -0,0 +1,337 @@
+package record
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/al-pi314/gogo/clock"
+	"github.com/al-pi314/gogo/rules"
+	"github.com/pkg/errors"
+)
+
+const sgfLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+var sgfRulesets = map[rules.Ruleset]string{
+	rules.ChineseRules:  "Chinese",
+	rules.JapaneseRules: "Japanese",
+	rules.AGARules:      "AGA",
+}
+
+// ------------------------------------ Helper Functions ------------------------------------ \\
+func sgfText(v string) string {
+	v = strings.ReplaceAll(v, "\\", "\\\\")
+	return strings.ReplaceAll(v, "]", "\\]")
+}
+
+func sgfPoint(x, y int) string {
+	return string([]byte{sgfLetters[x], sgfLetters[y]})
+}
+
+// parsePoint returns point cordinates. Nil cordinates represent a skip.
+func parsePoint(v string, dymension int) (*int, *int, error) {
+	if v == "" || (v == "tt" && dymension <= 19) {
+		return nil, nil, nil
+	}
+	if len(v) != 2 {
+		return nil, nil, errors.Errorf("invalid sgf point %q", v)
+	}
+	x := strings.IndexByte(sgfLetters, v[0])
+	y := strings.IndexByte(sgfLetters, v[1])
+	if x < 0 || y < 0 || x >= dymension || y >= dymension {
+		return nil, nil, errors.Errorf("sgf point %q is outside of the board", v)
+	}
+	return &x, &y, nil
+}
+
+// parsePoints returns points of a point list value. Compressed point lists (aa:cc) are expanded.
+func parsePoints(values []string, dymension int) ([]rules.Cordinate, error) {
+	points := []rules.Cordinate{}
+	for _, v := range values {
+		from, to, found := strings.Cut(v, ":")
+		if !found {
+			to = from
+		}
+		x1, y1, err := parsePoint(from, dymension)
+		if err != nil {
+			return nil, err
+		}
+		x2, y2, err := parsePoint(to, dymension)
+		if err != nil {
+			return nil, err
+		}
+		if x1 == nil || x2 == nil {
+			continue
+		}
+		for y := *y1; y <= *y2; y++ {
+			for x := *x1; x <= *x2; x++ {
+				points = append(points, rules.Cordinate{X: x, Y: y})
+			}
+		}
+	}
+	return points, nil
+}
+
+type sgfNode map[string][]string
+
+type sgfParser struct {
+	data []byte
+	pos  int
+}
+
+func (p *sgfParser) peek() byte {
+	for p.pos < len(p.data) && strings.IndexByte(" \t\r\n", p.data[p.pos]) >= 0 {
+		p.pos++
+	}
+	if p.pos >= len(p.data) {
+		return 0
+	}
+	return p.data[p.pos]
+}
+
+// mainLine parses a game tree and returns nodes of its first variation.
+func (p *sgfParser) mainLine() ([]sgfNode, error) {
+	if p.peek() != '(' {
+		return nil, errors.Errorf("expected '(' at position %d", p.pos)
+	}
+	p.pos++
+
+	nodes := []sgfNode{}
+	for p.peek() == ';' {
+		p.pos++
+		n, err := p.node()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+
+	// only the first variation is followed
+	for i := 0; p.peek() == '('; i++ {
+		variation, err := p.mainLine()
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			nodes = append(nodes, variation...)
+		}
+	}
+
+	if p.peek() != ')' {
+		return nil, errors.Errorf("expected ')' at position %d", p.pos)
+	}
+	p.pos++
+	return nodes, nil
+}
+
+func (p *sgfParser) node() (sgfNode, error) {
+	n := sgfNode{}
+	for {
+		// lowercase letters are allowed in old property names and ignored
+		ident := ""
+		for c := p.peek(); (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z'); c = p.data[p.pos] {
+			if c >= 'A' && c <= 'Z' {
+				ident += string(c)
+			}
+			p.pos++
+			if p.pos >= len(p.data) {
+				break
+			}
+		}
+		if ident == "" {
+			return n, nil
+		}
+
+		if p.peek() != '[' {
+			return nil, errors.Errorf("property %s has no value", ident)
+		}
+		for p.peek() == '[' {
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			n[ident] = append(n[ident], v)
+		}
+	}
+}
+
+func (p *sgfParser) value() (string, error) {
+	p.pos++
+	v := strings.Builder{}
+	for ; p.pos < len(p.data); p.pos++ {
+		c := p.data[p.pos]
+		switch c {
+		case '\\':
+			p.pos++
+			// escaped line breaks are removed
+			if p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				v.WriteByte(p.data[p.pos])
+			}
+		case ']':
+			p.pos++
+			return v.String(), nil
+		default:
+			v.WriteByte(c)
+		}
+	}
+	return "", errors.New("unterminated property value")
+}
+
+// ------------------------------------ ----------------- ------------------------------------ \\
+
+// WriteSGF writes game record in SGF (FF[4]) format.
+func (r Record) WriteSGF(w io.Writer) error {
+	if r.Dymension <= 0 || r.Dymension > len(sgfLetters) {
+		return errors.Errorf("board dymension %d can not be written to sgf", r.Dymension)
+	}
+
+	b := strings.Builder{}
+	b.WriteString("(;FF[4]GM[1]CA[UTF-8]AP[gogo]")
+	fmt.Fprintf(&b, "SZ[%d]KM[%g]RU[%s]", r.Dymension, r.Komi, sgfRulesets[r.Ruleset])
+	if r.Handicap > 1 {
+		fmt.Fprintf(&b, "HA[%d]", r.Handicap)
+	}
+	if r.WhiteName != "" {
+		fmt.Fprintf(&b, "PW[%s]", sgfText(r.WhiteName))
+	}
+	if r.BlackName != "" {
+		fmt.Fprintf(&b, "PB[%s]", sgfText(r.BlackName))
+	}
+	if r.Time != nil {
+		fmt.Fprintf(&b, "DT[%s]", r.Time.Format("2006-01-02"))
+	}
+	if r.Result != nil {
+		fmt.Fprintf(&b, "RE[%s]", sgfText(r.Result.String()))
+	}
+	if r.TimeControl.System != clock.NoTime {
+		fmt.Fprintf(&b, "TM[%g]", r.TimeControl.MainTime.Seconds())
+		if ot := r.TimeControl.Overtime(); ot != "" {
+			fmt.Fprintf(&b, "OT[%s]", sgfText(ot))
+		}
+	}
+	if len(r.Setup) > 0 {
+		b.WriteString("AB")
+		for _, c := range r.Setup {
+			fmt.Fprintf(&b, "[%s]", sgfPoint(c.X, c.Y))
+		}
+	}
+	b.WriteString("\n")
+
+	// records without move colors alternate moves, white moves first after handicap setup
+	white := len(r.Setup) > 0
+	for i, m := range r.Moves {
+		if i < len(r.MoveColors) {
+			white = r.MoveColors[i]
+		}
+		color := "B"
+		if white {
+			color = "W"
+		}
+		point := ""
+		if m[0] != nil && m[1] != nil {
+			point = sgfPoint(*m[0], *m[1])
+		}
+		fmt.Fprintf(&b, ";%s[%s]", color, point)
+		white = !white
+	}
+	b.WriteString(")\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ReadSGF reads main line of the first game in SGF data.
+func ReadSGF(data []byte) (Record, error) {
+	p := sgfParser{data: data}
+	nodes, err := p.mainLine()
+	if err != nil {
+		return Record{}, errors.Wrap(err, "invalid sgf structure")
+	}
+	if len(nodes) == 0 {
+		return Record{}, errors.New("sgf game has no nodes")
+	}
+
+	root := nodes[0]
+	first := func(ident string) string {
+		if v, ok := root[ident]; ok && len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	r := Record{
+		Dymension: 19,
+		WhiteName: first("PW"),
+		BlackName: first("PB"),
+	}
+	if v := first("RE"); v != "" {
+		result := rules.ParseResult(v)
+		r.Result = &result
+	}
+	if v := first("SZ"); v != "" {
+		// rectangular boards are not supported
+		size, _, _ := strings.Cut(v, ":")
+		if r.Dymension, err = strconv.Atoi(strings.TrimSpace(size)); err != nil {
+			return Record{}, errors.Wrap(err, "invalid board size")
+		}
+	}
+	if r.Dymension <= 0 || r.Dymension > len(sgfLetters) {
+		return Record{}, errors.Errorf("unsupported board size %d", r.Dymension)
+	}
+	if v := first("KM"); v != "" {
+		if r.Komi, err = strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+			return Record{}, errors.Wrap(err, "invalid komi")
+		}
+	}
+	if v := first("HA"); v != "" {
+		if r.Handicap, err = strconv.Atoi(strings.TrimSpace(v)); err != nil {
+			return Record{}, errors.Wrap(err, "invalid handicap")
+		}
+	}
+	if v := first("DT"); len(v) >= 10 {
+		if t, err := time.Parse("2006-01-02", v[:10]); err == nil {
+			r.Time = &t
+		}
+	}
+	for ruleset, name := range sgfRulesets {
+		if strings.EqualFold(first("RU"), name) {
+			r.Ruleset = ruleset
+		}
+	}
+	if v := first("TM"); v != "" {
+		r.TimeControl = clock.ParseSettings(v, first("OT"))
+	}
+
+	for _, n := range nodes {
+		// only black setup pieces placed before the first move are supported
+		for _, ident := range []string{"AW", "AE"} {
+			if _, ok := n[ident]; ok {
+				return Record{}, errors.Errorf("unsupported sgf setup property %s", ident)
+			}
+		}
+		if _, ok := n["AB"]; ok && len(r.Moves) > 0 {
+			return Record{}, errors.New("unsupported sgf setup property AB after the first move")
+		}
+		setup, err := parsePoints(n["AB"], r.Dymension)
+		if err != nil {
+			return Record{}, err
+		}
+		r.Setup = append(r.Setup, setup...)
+
+		for _, color := range []string{"B", "W"} {
+			v, ok := n[color]
+			if !ok || len(v) == 0 {
+				continue
+			}
+			x, y, err := parsePoint(v[0], r.Dymension)
+			if err != nil {
+				return Record{}, err
+			}
+			r.Moves = append(r.Moves, [2]*int{x, y})
+			r.MoveColors = append(r.MoveColors, color == "W")
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,162 @@
+package record
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/al-pi314/gogo/clock"
+	"github.com/al-pi314/gogo/rules"
+)
+
+func point(x, y int) [2]*int {
+	return [2]*int{&x, &y}
+}
+
+func TestSGFRoundTrip(t *testing.T) {
+	date := time.Date(2022, 5, 14, 0, 0, 0, 0, time.UTC)
+	white, black := true, false
+
+	tests := []struct {
+		name   string
+		record Record
+	}{
+		{
+			name: "moves and passes",
+			record: Record{
+				Dymension:  9,
+				Komi:       6.5,
+				Ruleset:    rules.JapaneseRules,
+				Moves:      [][2]*int{point(2, 2), point(6, 6), {nil, nil}, {nil, nil}},
+				MoveColors: []bool{false, true, false, true},
+			},
+		},
+		{
+			name: "game information",
+			record: Record{
+				Time:        &date,
+				Dymension:   13,
+				Komi:        0.5,
+				Ruleset:     rules.AGARules,
+				WhiteName:   "agent [0]",
+				BlackName:   "human\\player",
+				Moves:       [][2]*int{point(0, 12)},
+				MoveColors:  []bool{false},
+				Result:      &rules.Result{Winner: &white, Reason: rules.ByResign},
+				TimeControl: clock.Settings{System: clock.ByoYomi, MainTime: 10 * time.Minute, Period: 30 * time.Second, Periods: 5},
+			},
+		},
+		{
+			name: "handicap setup",
+			record: Record{
+				Dymension:  9,
+				Handicap:   2,
+				Ruleset:    rules.ChineseRules,
+				Setup:      []rules.Cordinate{{X: 2, Y: 6}, {X: 6, Y: 2}},
+				Moves:      [][2]*int{point(4, 4), point(3, 3)},
+				MoveColors: []bool{true, false},
+				Result:     &rules.Result{Winner: &black, Margin: 12.5},
+			},
+		},
+		{
+			name: "free handicap placed with moves",
+			record: Record{
+				Dymension:   9,
+				Handicap:    2,
+				Moves:       [][2]*int{point(2, 6), point(6, 2), point(4, 4)},
+				MoveColors:  []bool{false, false, true},
+				TimeControl: clock.Settings{System: clock.Canadian, MainTime: time.Minute, Period: 5 * time.Minute, Stones: 25},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := bytes.Buffer{}
+			if err := test.record.WriteSGF(&buf); err != nil {
+				t.Fatalf("failed to write sgf: %v", err)
+			}
+			r, err := ReadSGF(buf.Bytes())
+			if err != nil {
+				t.Fatalf("failed to read sgf %q: %v", buf.String(), err)
+			}
+			if !reflect.DeepEqual(r, test.record) {
+				t.Fatalf("read record %+v, expected %+v (sgf %q)", r, test.record, buf.String())
+			}
+		})
+	}
+}
+
+func TestReadSGF(t *testing.T) {
+	tests := []struct {
+		name string
+		sgf  string
+		// valid records have the given moves and setup
+		valid bool
+		moves [][2]*int
+		setup []rules.Cordinate
+	}{
+		{
+			name:  "first variation",
+			sgf:   "(;SZ[9];B[cc](;W[gg])(;W[dd]))",
+			valid: true,
+			moves: [][2]*int{point(2, 2), point(6, 6)},
+		},
+		{
+			name:  "compressed setup points",
+			sgf:   "(;SZ[9]AB[aa:bb];W[ee])",
+			valid: true,
+			moves: [][2]*int{point(4, 4)},
+			setup: []rules.Cordinate{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}},
+		},
+		{
+			name:  "tt pass",
+			sgf:   "(;SZ[19];B[tt];W[])",
+			valid: true,
+			moves: [][2]*int{{nil, nil}, {nil, nil}},
+		},
+		{
+			name: "white setup",
+			sgf:  "(;SZ[9]AW[cc];B[dd])",
+		},
+		{
+			name: "cleared setup",
+			sgf:  "(;SZ[9];B[dd];W[ee]AE[dd])",
+		},
+		{
+			name: "black setup after the first move",
+			sgf:  "(;SZ[9];B[dd];AB[ee])",
+		},
+		{
+			name: "point outside of the board",
+			sgf:  "(;SZ[9];B[jj])",
+		},
+		{
+			name: "unsupported board size",
+			sgf:  "(;SZ[60])",
+		},
+		{
+			name: "unterminated value",
+			sgf:  "(;SZ[9];B[dd",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := ReadSGF([]byte(test.sgf))
+			if !test.valid {
+				if err == nil {
+					t.Fatalf("expected error, read record %+v", r)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to read sgf: %v", err)
+			}
+			if !reflect.DeepEqual(r.Moves, test.moves) || !reflect.DeepEqual(r.Setup, test.setup) {
+				t.Fatalf("read moves %v and setup %v, expected %v and %v", r.Moves, r.Setup, test.moves, test.setup)
+			}
+		})
+	}
+}
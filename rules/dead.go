@@ -0,0 +1,129 @@
+package rules
+
+import "math/rand"
+
+const (
+	// estimatePlayouts is number of random games played to estimate dead pieces.
+	estimatePlayouts = 64
+	// deadThreshold is share of playouts in which the opponent owns the piece for it to be considered dead.
+	deadThreshold = 0.6
+)
+
+// IsDead returns wether the piece on the given position was marked dead.
+func (s *GameState) IsDead(x, y int) bool {
+	return s.Dead[Cordinate{x, y}]
+}
+
+// ToggleDead marks or unmarks the whole group on the given position as dead. Pieces can only be marked when
+// the game is finished, returns false otherwise or when the position is empty.
+func (s *GameState) ToggleDead(x, y int) bool {
+	if !s.Finished || s.PieceAt(x, y) == nil {
+		return false
+	}
+	dymension := len(s.Board)
+	s.setDead(s.groups.members[s.groups.find(y*dymension+x)].stones, !s.IsDead(x, y))
+	return true
+}
+
+func (s *GameState) setDead(stones []int, dead bool) {
+	if s.Dead == nil {
+		s.Dead = map[Cordinate]bool{}
+	}
+	dymension := len(s.Board)
+	for _, p := range stones {
+		c := Cordinate{p % dymension, p / dymension}
+		if dead {
+			s.Dead[c] = true
+		} else {
+			delete(s.Dead, c)
+		}
+	}
+}
+
+// IsEye returns wether the empty position is surrounded by pieces of the player so filling it would only take
+// away its own liberty. At most one diagonal may belong to the opponent (none on the edge).
+func (s *GameState) IsEye(x, y int, white bool) bool {
+	if !s.inBounds(x, y) || s.Board[y][x] != nil {
+		return false
+	}
+	for _, d := range [4]Cordinate{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		piece := s.PieceAt(x+d.X, y+d.Y)
+		if s.inBounds(x+d.X, y+d.Y) && (piece == nil || *piece != white) {
+			return false
+		}
+	}
+
+	opponent, outside := 0, 0
+	for _, d := range [4]Cordinate{{-1, -1}, {1, -1}, {-1, 1}, {1, 1}} {
+		if !s.inBounds(x+d.X, y+d.Y) {
+			outside = 1
+			continue
+		}
+		if piece := s.PieceAt(x+d.X, y+d.Y); piece != nil && *piece != white {
+			opponent++
+		}
+	}
+	return opponent+outside < 2
+}
+
+// EstimateDead marks groups as dead when the opponent owns them at the end of most random playouts. Previous
+// marks are replaced. Estimate is deterministic for the same position.
+func (s *GameState) EstimateDead() {
+	if !s.Finished {
+		return
+	}
+	dymension := len(s.Board)
+	rng := rand.New(rand.NewSource(int64(s.Hash)))
+	opponentOwned := make([]int, dymension*dymension)
+	for i := 0; i < estimatePlayouts; i++ {
+		owners := s.Playout(rng).owners()
+		for p := range opponentOwned {
+			if s.groups.parent[p] < 0 {
+				continue
+			}
+			if (s.groups.white[p] && owners[p] == ownerBlack) || (!s.groups.white[p] && owners[p] == ownerWhite) {
+				opponentOwned[p]++
+			}
+		}
+	}
+
+	s.Dead = nil
+	for _, grp := range s.groups.members {
+		owned := 0
+		for _, p := range grp.stones {
+			owned += opponentOwned[p]
+		}
+		if float64(owned) > deadThreshold*float64(estimatePlayouts*len(grp.stones)) {
+			s.setDead(grp.stones, true)
+		}
+	}
+}
+
+// Playout returns copy of the game continued with random moves that do not fill own eyes until both players
+// skip. Finished games are continued as well.
+func (s *GameState) Playout(rng *rand.Rand) *GameState {
+	c := s.Clone()
+	c.Finished = false
+	c.OpponentSkipped = false
+	c.Dead = nil
+
+	dymension := len(s.Board)
+	skips := 0
+	for moves := 0; moves < 3*dymension*dymension && skips < 2; moves++ {
+		placed := false
+		for _, p := range rng.Perm(dymension * dymension) {
+			x, y := p%dymension, p/dymension
+			if !c.IsEye(x, y, c.WhiteToMove) && c.Place(x, y) {
+				placed = true
+				break
+			}
+		}
+		if placed {
+			skips = 0
+			continue
+		}
+		c.WhiteToMove = !c.WhiteToMove
+		skips++
+	}
+	return c
+}
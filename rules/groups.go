@@ -0,0 +1,193 @@
+package rules
+
+type group struct {
+	stones    []int
+	liberties map[int]bool
+}
+
+// groups tracks connected pieces and their liberties so moves are checked and applied in time proportional
+// to the size of affected groups. Positions are indexed as y*dymension+x.
+type groups struct {
+	dymension int
+	// parent links pieces of the same group (union-find), empty positions are set to -1
+	parent  []int
+	white   []bool
+	members map[int]*group
+}
+
+func newGroups(dymension int) *groups {
+	g := groups{
+		dymension: dymension,
+		parent:    make([]int, dymension*dymension),
+		white:     make([]bool, dymension*dymension),
+		members:   map[int]*group{},
+	}
+	for p := range g.parent {
+		g.parent[p] = -1
+	}
+	return &g
+}
+
+func (g *groups) clone() *groups {
+	c := groups{
+		dymension: g.dymension,
+		parent:    append([]int{}, g.parent...),
+		white:     append([]bool{}, g.white...),
+		members:   make(map[int]*group, len(g.members)),
+	}
+	for root, grp := range g.members {
+		liberties := make(map[int]bool, len(grp.liberties))
+		for p := range grp.liberties {
+			liberties[p] = true
+		}
+		c.members[root] = &group{
+			stones:    append([]int{}, grp.stones...),
+			liberties: liberties,
+		}
+	}
+	return &c
+}
+
+// neighbours returns positions next to the position and their count.
+func (g *groups) neighbours(p int) ([4]int, int) {
+	ns := [4]int{}
+	n := 0
+	x, y := p%g.dymension, p/g.dymension
+	if x > 0 {
+		ns[n] = p - 1
+		n++
+	}
+	if x < g.dymension-1 {
+		ns[n] = p + 1
+		n++
+	}
+	if y > 0 {
+		ns[n] = p - g.dymension
+		n++
+	}
+	if y < g.dymension-1 {
+		ns[n] = p + g.dymension
+		n++
+	}
+	return ns, n
+}
+
+func (g *groups) find(p int) int {
+	for g.parent[p] != p {
+		g.parent[p] = g.parent[g.parent[p]]
+		p = g.parent[p]
+	}
+	return p
+}
+
+func (g *groups) union(a, b int) int {
+	a, b = g.find(a), g.find(b)
+	if a == b {
+		return a
+	}
+	// merge smaller group into the larger one
+	if len(g.members[a].stones) < len(g.members[b].stones) {
+		a, b = b, a
+	}
+	g.parent[b] = a
+	g.members[a].stones = append(g.members[a].stones, g.members[b].stones...)
+	for p := range g.members[b].liberties {
+		g.members[a].liberties[p] = true
+	}
+	delete(g.members, b)
+	return a
+}
+
+// captures returns roots of opponent groups left without liberties by a piece placed on the empty position.
+func (g *groups) captures(p int, white bool) []int {
+	roots := []int{}
+	ns, n := g.neighbours(p)
+	for _, q := range ns[:n] {
+		if g.parent[q] < 0 || g.white[q] == white {
+			continue
+		}
+		root := g.find(q)
+		if len(g.members[root].liberties) != 1 {
+			continue
+		}
+		found := false
+		for _, r := range roots {
+			found = found || r == root
+		}
+		if !found {
+			roots = append(roots, root)
+		}
+	}
+	return roots
+}
+
+// hasRoom returns wether a piece placed on the empty position would have liberties without capturing.
+func (g *groups) hasRoom(p int, white bool) bool {
+	ns, n := g.neighbours(p)
+	for _, q := range ns[:n] {
+		if g.parent[q] < 0 {
+			return true
+		}
+		if g.white[q] == white && len(g.members[g.find(q)].liberties) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// place adds a piece to the empty position and returns positions of captured opponent pieces.
+func (g *groups) place(p int, white bool) []int {
+	g.parent[p] = p
+	g.white[p] = white
+	g.members[p] = &group{
+		stones:    []int{p},
+		liberties: map[int]bool{},
+	}
+
+	ns, n := g.neighbours(p)
+	for _, q := range ns[:n] {
+		if g.parent[q] < 0 {
+			g.members[p].liberties[q] = true
+			continue
+		}
+		delete(g.members[g.find(q)].liberties, p)
+	}
+
+	root := p
+	for _, q := range ns[:n] {
+		if g.parent[q] >= 0 && g.white[q] == white {
+			root = g.union(root, q)
+		}
+	}
+
+	captured := []int{}
+	for _, q := range ns[:n] {
+		if g.parent[q] < 0 || g.white[q] == white {
+			continue
+		}
+		if r := g.find(q); len(g.members[r].liberties) == 0 {
+			captured = append(captured, g.remove(r)...)
+		}
+	}
+	return captured
+}
+
+// remove removes the group and returns positions of its pieces.
+func (g *groups) remove(root int) []int {
+	stones := g.members[root].stones
+	delete(g.members, root)
+	for _, p := range stones {
+		g.parent[p] = -1
+	}
+
+	// removed pieces become liberties of surrounding groups
+	for _, p := range stones {
+		ns, n := g.neighbours(p)
+		for _, q := range ns[:n] {
+			if g.parent[q] >= 0 {
+				g.members[g.find(q)].liberties[p] = true
+			}
+		}
+	}
+	return stones
+}
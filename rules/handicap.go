@@ -0,0 +1,37 @@
+package rules
+
+// HandicapPoints returns star points used for fixed handicap placement. Returns fewer points when the board
+// does not support the requested number of pieces (boards smaller than 7, more than 4 pieces on even boards
+// or more than 9 pieces).
+func HandicapPoints(dymension, pieces int) []Cordinate {
+	if dymension < 7 || pieces < 2 {
+		return nil
+	}
+
+	edge := 2
+	if dymension >= 13 {
+		edge = 3
+	}
+	low, high, mid := edge, dymension-1-edge, dymension/2
+
+	maxPieces := 9
+	if dymension%2 == 0 {
+		maxPieces = 4
+	}
+	if pieces > maxPieces {
+		pieces = maxPieces
+	}
+
+	points := []Cordinate{{low, high}, {high, low}, {low, low}, {high, high}}
+	if pieces >= 6 {
+		points = append(points, Cordinate{low, mid}, Cordinate{high, mid})
+	}
+	if pieces >= 8 {
+		points = append(points, Cordinate{mid, high}, Cordinate{mid, low})
+	}
+	// odd number of pieces above 4 uses the center point
+	if pieces > 4 && pieces%2 == 1 {
+		points = append(points[:pieces-1], Cordinate{mid, mid})
+	}
+	return points[:pieces]
+}
@@ -0,0 +1,73 @@
+package rules
+
+import "fmt"
+
+// MoveKind tells what a player decided to do on their turn.
+type MoveKind int
+
+const (
+	PlayMove MoveKind = iota
+	PassMove
+	ResignMove
+)
+
+// Move is a decision of the player to move. Position is only used by played moves.
+type Move struct {
+	Kind MoveKind
+	X    int
+	Y    int
+}
+
+// Play returns move placing a piece on the given position.
+func Play(x, y int) Move {
+	return Move{Kind: PlayMove, X: x, Y: y}
+}
+
+// Pass returns move skipping the turn.
+func Pass() Move {
+	return Move{Kind: PassMove}
+}
+
+// Resign returns move giving up the game.
+func Resign() Move {
+	return Move{Kind: ResignMove}
+}
+
+func (m Move) String() string {
+	switch m.Kind {
+	case PassMove:
+		return "pass"
+	case ResignMove:
+		return "resign"
+	}
+	return fmt.Sprintf("(%d, %d)", m.X, m.Y)
+}
+
+// Apply makes the move for the player to move. Returns false when the move is not legal or the game is
+// finished.
+func (s *GameState) Apply(m Move) bool {
+	if s.Finished {
+		return false
+	}
+	switch m.Kind {
+	case PassMove:
+		s.Skip()
+		return true
+	case ResignMove:
+		s.Resign()
+		return true
+	}
+	return s.Place(m.X, m.Y)
+}
+
+// ApplyAs makes the move for the given player, game records can contain consecutive moves of the same player.
+// Returns false when the move is not legal or the game is finished.
+func (s *GameState) ApplyAs(m Move, white bool) bool {
+	toMove := s.WhiteToMove
+	s.WhiteToMove = white
+	if !s.Apply(m) {
+		s.WhiteToMove = toMove
+		return false
+	}
+	return true
+}
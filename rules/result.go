@@ -0,0 +1,115 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Reason tells how the game ended.
+type Reason int
+
+const (
+	// ByScore games ended with consecutive skips and were counted.
+	ByScore Reason = iota
+	// ByResign games ended when a player resigned.
+	ByResign
+	// ByTimeout games ended when a player ran out of time.
+	ByTimeout
+	// ByMoveLimit games were stopped at the move limit and counted.
+	ByMoveLimit
+)
+
+var reasonNames = map[Reason]string{
+	ByScore:     "score",
+	ByResign:    "resign",
+	ByTimeout:   "timeout",
+	ByMoveLimit: "move limit",
+}
+
+func (r Reason) String() string {
+	return reasonNames[r]
+}
+
+// Result holds the outcome of a finished game.
+type Result struct {
+	// Winner is true for white and false for black, draws have no winner.
+	Winner *bool
+	// Margin is score difference of counted games.
+	Margin float64
+	Reason Reason
+}
+
+// String returns result in the notation used by SGF and GTP (W+3.5, B+R, W+T or 0 for a draw).
+func (r Result) String() string {
+	if r.Winner == nil {
+		return "0"
+	}
+	winner := "B"
+	if *r.Winner {
+		winner = "W"
+	}
+	switch r.Reason {
+	case ByResign:
+		return winner + "+R"
+	case ByTimeout:
+		return winner + "+T"
+	}
+	return fmt.Sprintf("%s+%g", winner, r.Margin)
+}
+
+// ParseResult parses result notation used by SGF and GTP. Unknown results are treated as draws.
+func ParseResult(value string) Result {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) < 2 || value[1] != '+' || (value[0] != 'W' && value[0] != 'B') {
+		return Result{}
+	}
+
+	white := value[0] == 'W'
+	r := Result{Winner: &white}
+	switch value[2:] {
+	case "R", "RESIGN":
+		r.Reason = ByResign
+	case "T", "TIME":
+		r.Reason = ByTimeout
+	default:
+		r.Margin, _ = strconv.ParseFloat(value[2:], 64)
+	}
+	return r
+}
+
+// Result returns outcome of the game. Counted games are scored on every call so dead pieces marked after the
+// game finished are taken into account.
+func (s *GameState) Result() Result {
+	if s.Outcome != nil && s.Outcome.Reason != ByMoveLimit {
+		return *s.Outcome
+	}
+
+	score := s.Score()
+	r := Result{Margin: math.Abs(score)}
+	if s.Outcome != nil {
+		r.Reason = s.Outcome.Reason
+	}
+	if score != 0 {
+		white := score > 0
+		r.Winner = &white
+	}
+	return r
+}
+
+// End finishes the game with the given outcome. Winner and margin of games stopped at the move limit are
+// counted from the board.
+func (s *GameState) End(outcome Result) {
+	if s.Finished {
+		return
+	}
+	s.Finished = true
+	s.Outcome = &outcome
+}
+
+// Resign finishes the game, player to move resigns.
+func (s *GameState) Resign() {
+	winner := !s.WhiteToMove
+	s.End(Result{Winner: &winner, Reason: ByResign})
+}
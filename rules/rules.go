@@ -0,0 +1,326 @@
+package rules
+
+import "strings"
+
+type Cordinate struct {
+	X int
+	Y int
+}
+
+// Superko selects which repeated positions are forbidden. Simple ko only forbids immediate recapture.
+type Superko int
+
+const (
+	SimpleKo Superko = iota
+	// PositionalSuperko forbids moves recreating any previous board position.
+	PositionalSuperko
+	// SituationalSuperko forbids moves recreating any previous board position with the same player to move.
+	SituationalSuperko
+)
+
+var superkoRules = map[string]Superko{
+	"SIMPLE":      SimpleKo,
+	"POSITIONAL":  PositionalSuperko,
+	"SITUATIONAL": SituationalSuperko,
+}
+
+// SuperkoByName returns superko rule by name. Unknown names default to simple ko.
+func SuperkoByName(name string) Superko {
+	if r, ok := superkoRules[strings.ToUpper(name)]; ok {
+		return r
+	}
+	return SimpleKo
+}
+
+// Settings configure rules used by the game.
+type Settings struct {
+	Superko  Superko
+	Ruleset  Ruleset
+	Komi     float64
+	Handicap int
+	// FreeHandicap lets black place handicap pieces anywhere instead of on star points.
+	FreeHandicap bool
+	// HandicapSetup places handicap pieces on the given positions instead of on star points.
+	HandicapSetup []Cordinate
+	// MoveLimit stops the game after the given number of moves, zero means no limit.
+	MoveLimit int
+}
+
+// GameState holds board position and counters of a single game. It contains no rendering or player logic.
+type GameState struct {
+	Board               [][]*bool
+	Moves               [][2]*int
+	MovesCount          int
+	WhiteMoves          int  `encode:"true"`
+	BlackMoves          int  `encode:"true"`
+	OpponentSkipped     bool `encode:"true"`
+	BlackStones         int  `encode:"true"`
+	WhiteStones         int  `encode:"true"`
+	BlackStonesCaptured int  `encode:"true"`
+	WhiteStonesCaptured int  `encode:"true"`
+
+	// MoveColors holds color of the player that made each move (true for white).
+	MoveColors  []bool
+	WhitePasses int
+	BlackPasses int
+	WhiteToMove bool
+	Finished    bool
+	Settings    Settings
+	// Setup holds handicap pieces placed before the first move.
+	Setup []Cordinate
+	// Hash is zobrist hash of the board position.
+	Hash uint64
+	// Dead holds pieces marked dead after the game finished, they are scored as captured.
+	Dead map[Cordinate]bool
+	// Outcome holds result of games that did not end with consecutive skips.
+	Outcome *Result
+
+	delayLock    bool
+	locked       Cordinate
+	zobrist      *zobristTable
+	groups       *groups
+	positions    map[uint64]int
+	handicapLeft int
+}
+
+func NewGameState(dymension int, settings Settings) *GameState {
+	s := GameState{
+		Board:     make([][]*bool, dymension),
+		Moves:     [][2]*int{},
+		Settings:  settings,
+		locked:    Cordinate{-1, -1},
+		zobrist:   zobrist(dymension),
+		groups:    newGroups(dymension),
+		positions: map[uint64]int{},
+	}
+	for y := range s.Board {
+		s.Board[y] = make([]*bool, dymension)
+	}
+
+	points := settings.HandicapSetup
+	if len(points) == 0 {
+		points = HandicapPoints(dymension, settings.Handicap)
+	}
+	if settings.FreeHandicap {
+		s.handicapLeft = settings.Handicap
+	} else if len(points) > 0 {
+		for _, c := range points {
+			if !s.inBounds(c.X, c.Y) || s.Board[c.Y][c.X] != nil {
+				continue
+			}
+			black := false
+			s.Board[c.Y][c.X] = &black
+			s.groups.place(c.Y*dymension+c.X, black)
+			s.Hash ^= s.zobrist.piece(c, dymension, black)
+			s.Setup = append(s.Setup, c)
+		}
+		s.BlackStones = len(s.Setup)
+		s.WhiteToMove = true
+	}
+
+	s.positions[s.positionKey(s.Hash, s.WhiteToMove)]++
+	return &s
+}
+
+// ------------------------------------ Helper Functions ------------------------------------ \\
+func (s *GameState) inBounds(x, y int) bool {
+	return y >= 0 && y < len(s.Board) && x >= 0 && x < len(s.Board[y])
+}
+
+// PieceAt returns piece on the given position or nil for empty and out of bounds positions.
+func (s *GameState) PieceAt(x, y int) *bool {
+	if !s.inBounds(x, y) {
+		return nil
+	}
+	return s.Board[y][x]
+}
+
+func updateCtr(whitePtr, blackPtr *int, iswhite bool, cnt int) {
+	if iswhite {
+		blackPtr = whitePtr
+	}
+	*blackPtr += cnt
+}
+
+// ------------------------------------ ----------------- ------------------------------------ \\
+
+// -------------------------------------- Move Functions ------------------------------------- \\
+// positionKey returns key under which the position is remembered for the superko rule.
+func (s *GameState) positionKey(hash uint64, whiteToMove bool) uint64 {
+	if s.Settings.Superko == SituationalSuperko && whiteToMove {
+		return hash ^ s.zobrist.white
+	}
+	return hash
+}
+
+// check returns opponent pieces captured by the move and board hash after the move. Returns false when the
+// move is not legal. Board is left unchanged.
+func (s *GameState) check(x, y int, white bool) ([]Cordinate, uint64, bool) {
+	// out of bounds or already occupied spaces are invalid
+	if !s.inBounds(x, y) || s.Board[y][x] != nil {
+		return nil, 0, false
+	}
+	dymension := len(s.Board)
+	p := y*dymension + x
+	captured := []Cordinate{}
+	for _, root := range s.groups.captures(p, white) {
+		for _, q := range s.groups.members[root].stones {
+			captured = append(captured, Cordinate{q % dymension, q / dymension})
+		}
+	}
+
+	// would be eliminated when placed
+	if len(captured) == 0 && !s.groups.hasRoom(p, white) {
+		return nil, 0, false
+	}
+
+	// ko rule
+	if len(captured) == 1 && captured[0] == s.locked {
+		return nil, 0, false
+	}
+
+	// superko rule
+	hash := s.Hash ^ s.zobrist.piece(Cordinate{x, y}, dymension, white)
+	for _, c := range captured {
+		hash ^= s.zobrist.piece(c, dymension, !white)
+	}
+	if s.Settings.Superko != SimpleKo && s.positions[s.positionKey(hash, !white)] > 0 {
+		return nil, 0, false
+	}
+	return captured, hash, true
+}
+
+func (s *GameState) placePiece(x, y int, white bool) bool {
+	captured, hash, ok := s.check(x, y, white)
+	if !ok {
+		return false
+	}
+
+	// place piece
+	s.Board[y][x] = &white
+	s.groups.place(y*len(s.Board)+x, white)
+	if len(captured) == 1 {
+		s.delayLock = true
+		s.locked.X = x
+		s.locked.Y = y
+	}
+	for _, c := range captured {
+		s.Board[c.Y][c.X] = nil
+	}
+	s.Hash = hash
+
+	updateCtr(&s.WhiteStones, &s.BlackStones, white, 1)
+	updateCtr(&s.WhiteStones, &s.BlackStones, !white, -len(captured))
+	updateCtr(&s.WhiteStonesCaptured, &s.BlackStonesCaptured, !white, len(captured))
+	return true
+}
+
+func (s *GameState) endMove(move [2]*int, skip bool) {
+	// save move
+	s.MovesCount++
+	s.Moves = append(s.Moves, move)
+	s.MoveColors = append(s.MoveColors, s.WhiteToMove)
+
+	// consequitive skips end the game, AGA rules require white to skip last
+	if skip && s.OpponentSkipped && (s.Settings.Ruleset != AGARules || s.WhiteToMove) {
+		s.Finished = true
+	}
+	s.OpponentSkipped = false
+	if skip {
+		s.OpponentSkipped = true
+		if s.WhiteToMove {
+			s.WhiteMoves += 1
+			s.WhitePasses += 1
+		} else {
+			s.BlackMoves += 1
+			s.BlackPasses += 1
+		}
+	}
+
+	// lock unlocks after next successful move
+	if !s.delayLock {
+		s.locked.X = -1
+		s.locked.Y = -1
+	}
+	s.delayLock = false
+	// change player to move, black keeps placing free handicap pieces
+	if s.handicapLeft > 0 {
+		s.handicapLeft--
+	}
+	if skip {
+		s.handicapLeft = 0
+	}
+	if s.handicapLeft == 0 {
+		s.WhiteToMove = !s.WhiteToMove
+	}
+	s.positions[s.positionKey(s.Hash, s.WhiteToMove)]++
+
+	if s.Settings.MoveLimit > 0 && s.MovesCount >= s.Settings.MoveLimit {
+		s.End(Result{Reason: ByMoveLimit})
+	}
+}
+
+// Place places a piece of the player to move. Returns false when the move is not legal.
+func (s *GameState) Place(x, y int) bool {
+	if s.Finished || !s.placePiece(x, y, s.WhiteToMove) {
+		return false
+	}
+	s.endMove([2]*int{&x, &y}, false)
+	return true
+}
+
+// Clone returns a copy of the game state that can be changed independently.
+func (s *GameState) Clone() *GameState {
+	c := *s
+	c.Board = make([][]*bool, len(s.Board))
+	for y := range s.Board {
+		c.Board[y] = append([]*bool{}, s.Board[y]...)
+	}
+	c.Moves = append([][2]*int{}, s.Moves...)
+	c.MoveColors = append([]bool{}, s.MoveColors...)
+	c.groups = s.groups.clone()
+	c.positions = make(map[uint64]int, len(s.positions))
+	for k, v := range s.positions {
+		c.positions[k] = v
+	}
+	if s.Dead != nil {
+		c.Dead = make(map[Cordinate]bool, len(s.Dead))
+		for k, v := range s.Dead {
+			c.Dead[k] = v
+		}
+	}
+	return &c
+}
+
+// IsLegal returns wether the player to move can place a piece on the given position (respecting suicide and
+// ko rules).
+func (s *GameState) IsLegal(x, y int) bool {
+	if s.Finished {
+		return false
+	}
+	_, _, ok := s.check(x, y, s.WhiteToMove)
+	return ok
+}
+
+// LegalMoves returns all positions where the player to move can place a piece. Skipping is always legal.
+func (s *GameState) LegalMoves() []Cordinate {
+	moves := []Cordinate{}
+	for y := range s.Board {
+		for x := range s.Board[y] {
+			if s.IsLegal(x, y) {
+				moves = append(moves, Cordinate{x, y})
+			}
+		}
+	}
+	return moves
+}
+
+// Skip skips the move of the player to move. Two consecutive skips finish the game.
+func (s *GameState) Skip() {
+	if s.Finished {
+		return
+	}
+	s.endMove([2]*int{nil, nil}, true)
+}
+
+// -------------------------------------- -------------- ------------------------------------- \\
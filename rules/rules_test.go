@@ -0,0 +1,205 @@
+package rules
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPlace(t *testing.T) {
+	tests := []struct {
+		name      string
+		dymension int
+		settings  Settings
+		// moves are played before the tested move, players alternate starting with black
+		moves []Move
+		move  Move
+		legal bool
+		// captured positions are empty after the tested move
+		captured []Cordinate
+	}{
+		{
+			name:     "capture in the corner",
+			moves:    []Move{Play(1, 0), Play(0, 0)},
+			move:     Play(0, 1),
+			legal:    true,
+			captured: []Cordinate{{0, 0}},
+		},
+		{
+			name:  "suicide",
+			moves: []Move{Pass(), Play(1, 0), Pass(), Play(0, 1)},
+			move:  Play(0, 0),
+			legal: false,
+		},
+		{
+			name:     "suicide capturing pieces",
+			moves:    []Move{Play(2, 0), Play(1, 0), Play(1, 1), Play(0, 1)},
+			move:     Play(0, 0),
+			legal:    true,
+			captured: []Cordinate{{1, 0}},
+		},
+		{
+			name:  "simple ko",
+			moves: []Move{Play(1, 0), Play(2, 0), Play(0, 1), Play(3, 1), Play(1, 2), Play(2, 2), Play(2, 1), Play(1, 1)},
+			move:  Play(2, 1),
+			legal: false,
+		},
+		{
+			name:     "ko after another move",
+			moves:    []Move{Play(1, 0), Play(2, 0), Play(0, 1), Play(3, 1), Play(1, 2), Play(2, 2), Play(2, 1), Play(1, 1), Play(4, 4), Play(0, 4)},
+			move:     Play(2, 1),
+			legal:    true,
+			captured: []Cordinate{{1, 1}},
+		},
+		{
+			name:      "repeated position with simple ko",
+			dymension: 3,
+			moves:     []Move{Play(1, 0), Play(1, 1), Play(0, 1), Play(1, 2), Play(0, 2), Play(0, 0)},
+			move:      Play(0, 1),
+			legal:     true,
+			captured:  []Cordinate{{0, 0}},
+		},
+		{
+			name:      "repeated position with positional superko",
+			dymension: 3,
+			settings:  Settings{Superko: PositionalSuperko},
+			moves:     []Move{Play(1, 0), Play(1, 1), Play(0, 1), Play(1, 2), Play(0, 2), Play(0, 0)},
+			move:      Play(0, 1),
+			legal:     false,
+		},
+		{
+			name:      "repeated position with situational superko",
+			dymension: 3,
+			settings:  Settings{Superko: SituationalSuperko},
+			moves:     []Move{Play(1, 0), Play(1, 1), Play(0, 1), Play(1, 2), Play(0, 2), Play(0, 0)},
+			// position was repeated with the other player to move
+			move:     Play(0, 1),
+			legal:    true,
+			captured: []Cordinate{{0, 0}},
+		},
+		{
+			name:  "occupied position",
+			moves: []Move{Play(2, 2)},
+			move:  Play(2, 2),
+			legal: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dymension := test.dymension
+			if dymension == 0 {
+				dymension = 5
+			}
+			s := NewGameState(dymension, test.settings)
+			for _, m := range test.moves {
+				if !s.Apply(m) {
+					t.Fatalf("move %s is not legal", m)
+				}
+			}
+
+			before := s.Clone()
+			if legal := s.Apply(test.move); legal != test.legal {
+				t.Fatalf("move %s legal %t, expected %t", test.move, legal, test.legal)
+			}
+			if !test.legal {
+				if s.Hash != before.Hash || s.MovesCount != before.MovesCount {
+					t.Fatal("illegal move changed the game state")
+				}
+				return
+			}
+			for _, c := range test.captured {
+				if s.PieceAt(c.X, c.Y) != nil {
+					t.Errorf("piece on %v was not captured", c)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeGroups(t *testing.T) {
+	s := NewGameState(5, Settings{})
+	// three black groups are joined into a row by two moves
+	for _, x := range []int{0, 2, 4, 1} {
+		s.Apply(Play(x, 2))
+		s.Apply(Pass())
+	}
+	if l := s.Liberties(0, 2); l != 7 {
+		t.Fatalf("group has %d liberties before the last join, expected 7", l)
+	}
+	s.Apply(Play(3, 2))
+	for x := 0; x < 5; x++ {
+		if l := s.Liberties(x, 2); l != 10 {
+			t.Fatalf("piece on (%d, 2) has %d liberties, expected 10", x, l)
+		}
+	}
+
+	// white fills all liberties of the merged group
+	for i := 0; i < 10; i++ {
+		s.Apply(Play(i%5, 1+2*(i/5)))
+		if i < 9 {
+			s.Apply(Pass())
+		}
+	}
+	for x := 0; x < 5; x++ {
+		if s.PieceAt(x, 2) != nil {
+			t.Fatalf("piece on (%d, 2) was not captured", x)
+		}
+	}
+	if s.BlackStones != 0 {
+		t.Fatalf("black has %d pieces, expected 0", s.BlackStones)
+	}
+}
+
+// liberties counts liberties of the group on the position by flood fill.
+func liberties(s *GameState, x, y int) int {
+	white := *s.PieceAt(x, y)
+	visited := map[Cordinate]bool{{x, y}: true}
+	empty := map[Cordinate]bool{}
+	queue := []Cordinate{{x, y}}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for _, n := range []Cordinate{{c.X - 1, c.Y}, {c.X + 1, c.Y}, {c.X, c.Y - 1}, {c.X, c.Y + 1}} {
+			if !s.inBounds(n.X, n.Y) || visited[n] {
+				continue
+			}
+			piece := s.PieceAt(n.X, n.Y)
+			if piece == nil {
+				empty[n] = true
+			} else if *piece == white {
+				visited[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return len(empty)
+}
+
+func TestLibertiesRandomGames(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for game := 0; game < 50; game++ {
+		s := NewGameState(6, Settings{Superko: PositionalSuperko})
+		for move := 0; move < 80 && !s.Finished; move++ {
+			legal := s.LegalMoves()
+			if len(legal) == 0 {
+				s.Apply(Pass())
+				continue
+			}
+			c := legal[rng.Intn(len(legal))]
+			if !s.Apply(Play(c.X, c.Y)) {
+				t.Fatalf("legal move %v was not accepted", c)
+			}
+
+			for y := range s.Board {
+				for x := range s.Board[y] {
+					if s.Board[y][x] == nil {
+						continue
+					}
+					if got, want := s.Liberties(x, y), liberties(s, x, y); got != want {
+						t.Fatalf("game %d move %d: piece on (%d, %d) has %d liberties, expected %d", game, move, x, y, got, want)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package rules
+
+import "strings"
+
+// Ruleset selects how the final score is counted.
+type Ruleset int
+
+const (
+	// ChineseRules count stones on the board and surrounded territory (area scoring).
+	ChineseRules Ruleset = iota
+	// JapaneseRules count surrounded territory and captured prisoners (territory scoring).
+	JapaneseRules
+	// AGARules count territory and prisoners where each skip gives the opponent one prisoner.
+	AGARules
+)
+
+var rulesets = map[string]Ruleset{
+	"CHINESE":  ChineseRules,
+	"JAPANESE": JapaneseRules,
+	"AGA":      AGARules,
+}
+
+// RulesetByName returns scoring ruleset by name. Unknown names default to chinese rules.
+func RulesetByName(name string) Ruleset {
+	if r, ok := rulesets[strings.ToUpper(name)]; ok {
+		return r
+	}
+	return ChineseRules
+}
+
+// Breakdown holds score components of a single player.
+type Breakdown struct {
+	Stones    int
+	Territory int
+	Prisoners int
+	Komi      float64
+	Total     float64
+}
+
+const (
+	ownerNone int8 = iota
+	ownerWhite
+	ownerBlack
+)
+
+// alive returns wether there is a piece on the position that was not marked dead.
+func (s *GameState) alive(p int) bool {
+	if s.groups.parent[p] < 0 {
+		return false
+	}
+	dymension := len(s.Board)
+	return !s.Dead[Cordinate{p % dymension, p / dymension}]
+}
+
+// owners returns owner of every position. Alive pieces are owned by their player, empty positions and dead
+// pieces by the player surrounding them.
+func (s *GameState) owners() []int8 {
+	dymension := len(s.Board)
+	owners := make([]int8, dymension*dymension)
+	visited := make([]bool, dymension*dymension)
+	for start := range visited {
+		if s.alive(start) {
+			owners[start] = ownerBlack
+			if s.groups.white[start] {
+				owners[start] = ownerWhite
+			}
+			continue
+		}
+		if visited[start] {
+			continue
+		}
+
+		// flood fill empty region and record colors of its border
+		region, touchesWhite, touchesBlack := []int{}, false, false
+		queue := []int{start}
+		visited[start] = true
+		for len(queue) > 0 {
+			p := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			region = append(region, p)
+
+			ns, n := s.groups.neighbours(p)
+			for _, q := range ns[:n] {
+				switch {
+				case s.alive(q) && s.groups.white[q]:
+					touchesWhite = true
+				case s.alive(q):
+					touchesBlack = true
+				case !visited[q]:
+					visited[q] = true
+					queue = append(queue, q)
+				}
+			}
+		}
+
+		owner := ownerNone
+		if touchesWhite && !touchesBlack {
+			owner = ownerWhite
+		} else if touchesBlack && !touchesWhite {
+			owner = ownerBlack
+		}
+		for _, p := range region {
+			owners[p] = owner
+		}
+	}
+	return owners
+}
+
+// territory returns number of empty positions (including dead pieces) surrounded by each player.
+func (s *GameState) territory() (int, int) {
+	white, black := 0, 0
+	for p, owner := range s.owners() {
+		if s.alive(p) {
+			continue
+		}
+		switch owner {
+		case ownerWhite:
+			white++
+		case ownerBlack:
+			black++
+		}
+	}
+	return white, black
+}
+
+// Territory returns owner of every empty position and dead piece indexed by [y][x] (true for white). Alive
+// pieces and neutral positions are nil.
+func (s *GameState) Territory() [][]*bool {
+	dymension := len(s.Board)
+	white, black := true, false
+	territory := make([][]*bool, dymension)
+	for y := range territory {
+		territory[y] = make([]*bool, dymension)
+	}
+	for p, owner := range s.owners() {
+		if s.alive(p) {
+			continue
+		}
+		switch owner {
+		case ownerWhite:
+			territory[p/dymension][p%dymension] = &white
+		case ownerBlack:
+			territory[p/dymension][p%dymension] = &black
+		}
+	}
+	return territory
+}
+
+// ScoreBreakdown returns score components of white and black player counted by the game ruleset. Dead
+// pieces are removed from the board and counted as prisoners.
+func (s *GameState) ScoreBreakdown() (Breakdown, Breakdown) {
+	whiteTeritory, blackTeritory := s.territory()
+	deadWhite, deadBlack := 0, 0
+	for c, dead := range s.Dead {
+		if piece := s.PieceAt(c.X, c.Y); dead && piece != nil {
+			updateCtr(&deadWhite, &deadBlack, *piece, 1)
+		}
+	}
+	white := Breakdown{
+		Stones:    s.WhiteStones - deadWhite,
+		Territory: whiteTeritory,
+		Prisoners: s.BlackStonesCaptured + deadBlack,
+		Komi:      s.Settings.Komi,
+	}
+	black := Breakdown{
+		Stones:    s.BlackStones - deadBlack,
+		Territory: blackTeritory,
+		Prisoners: s.WhiteStonesCaptured + deadWhite,
+	}
+	if s.Settings.Ruleset == AGARules {
+		white.Prisoners += s.BlackPasses
+		black.Prisoners += s.WhitePasses
+	}
+
+	for _, b := range []*Breakdown{&white, &black} {
+		switch s.Settings.Ruleset {
+		case JapaneseRules, AGARules:
+			b.Total = float64(b.Territory+b.Prisoners) + b.Komi
+		default:
+			b.Total = float64(b.Stones+b.Territory) + b.Komi
+		}
+	}
+	return white, black
+}
+
+// FullScore calculates game score and score of both players.
+func (s *GameState) FullScore() (float64, float64, float64) {
+	white, black := s.ScoreBreakdown()
+	return white.Total - black.Total, white.Total, black.Total
+}
+
+// Score returns game score.
+func (s *GameState) Score() float64 {
+	gameScore, _, _ := s.FullScore()
+	return gameScore
+}
+
+// ResultString returns game result in the notation used by SGF and GTP (W+3.5, B+R or 0 for a draw).
+func (s *GameState) ResultString() string {
+	return s.Result().String()
+}
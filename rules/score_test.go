@@ -0,0 +1,78 @@
+package rules
+
+import "testing"
+
+func TestScoreBreakdown(t *testing.T) {
+	// black wall on the second and white wall on the fourth column, black territory holds a white piece and
+	// white captured a black piece in its territory, black passed three times and white once
+	moves := []Move{
+		Play(1, 0), Play(3, 0), Play(1, 1), Play(3, 1), Play(1, 2), Play(3, 2), Play(1, 3), Play(3, 3), Play(1, 4), Play(3, 4),
+		Pass(), Play(0, 2), Play(4, 2), Play(4, 1), Pass(), Play(4, 3), Pass(), Pass(),
+	}
+
+	tests := []struct {
+		name    string
+		ruleset Ruleset
+		dead    []Cordinate
+		white   Breakdown
+		black   Breakdown
+	}{
+		{
+			name:    "chinese",
+			ruleset: ChineseRules,
+			dead:    []Cordinate{{0, 2}},
+			white:   Breakdown{Stones: 7, Territory: 3, Prisoners: 1, Komi: 0.5, Total: 10.5},
+			black:   Breakdown{Stones: 5, Territory: 5, Prisoners: 1, Total: 10},
+		},
+		{
+			name:    "chinese without dead pieces",
+			ruleset: ChineseRules,
+			white:   Breakdown{Stones: 8, Territory: 3, Prisoners: 1, Komi: 0.5, Total: 11.5},
+			black:   Breakdown{Stones: 5, Territory: 0, Prisoners: 0, Total: 5},
+		},
+		{
+			name:    "japanese",
+			ruleset: JapaneseRules,
+			dead:    []Cordinate{{0, 2}},
+			white:   Breakdown{Stones: 7, Territory: 3, Prisoners: 1, Komi: 0.5, Total: 4.5},
+			black:   Breakdown{Stones: 5, Territory: 5, Prisoners: 1, Total: 6},
+		},
+		{
+			name:    "aga",
+			ruleset: AGARules,
+			dead:    []Cordinate{{0, 2}},
+			white:   Breakdown{Stones: 7, Territory: 3, Prisoners: 4, Komi: 0.5, Total: 7.5},
+			black:   Breakdown{Stones: 5, Territory: 5, Prisoners: 2, Total: 7},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewGameState(5, Settings{Ruleset: test.ruleset, Komi: 0.5})
+			for _, m := range moves {
+				if !s.Apply(m) {
+					t.Fatalf("move %s is not legal", m)
+				}
+			}
+			if !s.Finished {
+				t.Fatal("game did not finish")
+			}
+			for _, c := range test.dead {
+				if !s.ToggleDead(c.X, c.Y) {
+					t.Fatalf("piece on %v could not be marked dead", c)
+				}
+			}
+
+			white, black := s.ScoreBreakdown()
+			if white != test.white {
+				t.Errorf("white breakdown %+v, expected %+v", white, test.white)
+			}
+			if black != test.black {
+				t.Errorf("black breakdown %+v, expected %+v", black, test.black)
+			}
+			if score := s.Score(); score != test.white.Total-test.black.Total {
+				t.Errorf("score %.1f, expected %.1f", score, test.white.Total-test.black.Total)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package rules
+
+// Liberties returns number of liberties of the group on the given position. Empty positions have none.
+func (s *GameState) Liberties(x, y int) int {
+	if s.PieceAt(x, y) == nil {
+		return 0
+	}
+	return len(s.groups.members[s.groups.find(y*len(s.Board)+x)].liberties)
+}
+
+// Captures returns number of opponent pieces captured when the player to move places a piece on the position.
+// Returns false when the move is not legal.
+func (s *GameState) Captures(x, y int) (int, bool) {
+	if s.Finished {
+		return 0, false
+	}
+	captured, _, ok := s.check(x, y, s.WhiteToMove)
+	return len(captured), ok
+}
+
+// PiecesInAtari returns number of pieces of the player in groups with a single liberty.
+func (s *GameState) PiecesInAtari(white bool) int {
+	pieces := 0
+	for root, grp := range s.groups.members {
+		if s.groups.white[root] == white && len(grp.liberties) == 1 {
+			pieces += len(grp.stones)
+		}
+	}
+	return pieces
+}
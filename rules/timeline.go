@@ -0,0 +1,95 @@
+package rules
+
+// Timeline keeps game states after every move so moves can be taken back, replayed and branched into new
+// variations.
+type Timeline struct {
+	states  []*GameState
+	current int
+}
+
+func NewTimeline(state *GameState) *Timeline {
+	return &Timeline{
+		states: []*GameState{state},
+	}
+}
+
+// State returns the current game state. It must not be changed directly, use timeline moves instead.
+func (t *Timeline) State() *GameState {
+	return t.states[t.current]
+}
+
+// Current returns number of moves played up to the current state.
+func (t *Timeline) Current() int {
+	return t.current
+}
+
+// Len returns number of moves recorded in the timeline including the moves that were taken back.
+func (t *Timeline) Len() int {
+	return len(t.states) - 1
+}
+
+// move applies the move on a copy of the current state. Moves that were taken back are discarded.
+func (t *Timeline) move(apply func(*GameState) bool) bool {
+	next := t.State().Clone()
+	if !apply(next) {
+		return false
+	}
+	t.states = append(t.states[:t.current+1], next)
+	t.current++
+	return true
+}
+
+// Apply makes the move for the player to move. Returns false when the move is not legal.
+func (t *Timeline) Apply(m Move) bool {
+	return t.move(func(s *GameState) bool { return s.Apply(m) })
+}
+
+// ApplyAs makes the move for the given player. Returns false when the move is not legal.
+func (t *Timeline) ApplyAs(m Move, white bool) bool {
+	return t.move(func(s *GameState) bool { return s.ApplyAs(m, white) })
+}
+
+// Place places a piece of the player to move. Returns false when the move is not legal.
+func (t *Timeline) Place(x, y int) bool {
+	return t.Apply(Play(x, y))
+}
+
+// Skip skips the move of the player to move.
+func (t *Timeline) Skip() bool {
+	return t.Apply(Pass())
+}
+
+// Resign finishes the game, player to move resigns.
+func (t *Timeline) Resign() bool {
+	return t.Apply(Resign())
+}
+
+// End finishes the game with the given outcome.
+func (t *Timeline) End(outcome Result) bool {
+	return t.move(func(s *GameState) bool {
+		if s.Finished {
+			return false
+		}
+		s.End(outcome)
+		return true
+	})
+}
+
+// Undo takes back the last move.
+func (t *Timeline) Undo() bool {
+	return t.Seek(t.current - 1)
+}
+
+// Redo replays the move that was taken back.
+func (t *Timeline) Redo() bool {
+	return t.Seek(t.current + 1)
+}
+
+// Seek moves to the state after the given number of moves.
+func (t *Timeline) Seek(move int) bool {
+	if move < 0 || move >= len(t.states) {
+		return false
+	}
+	t.current = move
+	return true
+}
@@ -0,0 +1,70 @@
+package rules
+
+import (
+	"math/rand"
+	"sync"
+)
+
+type zobristTable struct {
+	pieces [][2]uint64
+	white  uint64
+	// ko and skipped keys are only used by Key, board hashes do not include them
+	ko      []uint64
+	skipped uint64
+}
+
+var (
+	zobristMu     sync.Mutex
+	zobristTables = map[int]*zobristTable{}
+)
+
+// zobrist returns hashing keys for the board dymension. Keys are generated from a fixed seed so hashes are
+// equal between runs.
+func zobrist(dymension int) *zobristTable {
+	zobristMu.Lock()
+	defer zobristMu.Unlock()
+
+	if t, ok := zobristTables[dymension]; ok {
+		return t
+	}
+
+	r := rand.New(rand.NewSource(int64(dymension)))
+	t := &zobristTable{
+		pieces: make([][2]uint64, dymension*dymension),
+		white:  r.Uint64(),
+	}
+	for i := range t.pieces {
+		t.pieces[i] = [2]uint64{r.Uint64(), r.Uint64()}
+	}
+	t.ko = make([]uint64, dymension*dymension)
+	for i := range t.ko {
+		t.ko[i] = r.Uint64()
+	}
+	t.skipped = r.Uint64()
+	zobristTables[dymension] = t
+	return t
+}
+
+func (t *zobristTable) piece(c Cordinate, dymension int, white bool) uint64 {
+	color := 0
+	if white {
+		color = 1
+	}
+	return t.pieces[c.Y*dymension+c.X][color]
+}
+
+// Key returns hash of the position together with the player to move, the ko point and wether the opponent
+// skipped, it can be used to cache positions. Superko history is not included.
+func (s *GameState) Key() uint64 {
+	key := s.Hash
+	if s.WhiteToMove {
+		key ^= s.zobrist.white
+	}
+	if s.inBounds(s.locked.X, s.locked.Y) {
+		key ^= s.zobrist.ko[s.locked.Y*len(s.Board)+s.locked.X]
+	}
+	if s.OpponentSkipped {
+		key ^= s.zobrist.skipped
+	}
+	return key
+}
@@ -1,19 +1,14 @@
 package gogo
 
-import "reflect"
+import (
+	"reflect"
 
-type GameState struct {
-	Board               [][]*bool
-	Moves               [][2]*int
-	MovesCount          int
-	WhiteMoves          int  `encode:"true"`
-	BlackMoves          int  `encode:"true"`
-	OpponentSkipped     bool `encode:"true"`
-	BlackStones         int  `encode:"true"`
-	WhiteStones         int  `encode:"true"`
-	BlackStonesCaptured int  `encode:"true"`
-	WhiteStonesCaptured int  `encode:"true"`
-}
+	"github.com/al-pi314/gogo/rules"
+)
+
+type GameState = rules.GameState
+
+type Move = rules.Move
 
 func GameStateSize() int {
 	t := reflect.TypeOf(GameState{})